@@ -12,6 +12,38 @@ CREATE TABLE task_statuses (
     code VARCHAR(20) NOT NULL UNIQUE
 );
 
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id INT NOT NULL,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE project_members (
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX idx_project_members_user_id ON project_members(user_id);
+
+CREATE TABLE task_series (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    rrule TEXT NOT NULL,
+    dtstart TIMESTAMP WITH TIME ZONE NOT NULL,
+    start_occurrence INT NOT NULL DEFAULT 1,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal',
+    assignee_id INT,
+    project_id INT REFERENCES projects(id) ON DELETE CASCADE,
+    parent_id INT
+);
+
 CREATE TABLE tasks (
     id SERIAL PRIMARY KEY,
     title VARCHAR(255) NOT NULL,
@@ -19,11 +51,202 @@ CREATE TABLE tasks (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     deadline TIMESTAMP WITH TIME ZONE,
     user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    status_id INT NOT NULL REFERENCES task_statuses(id) ON DELETE RESTRICT
+    status_id INT NOT NULL REFERENCES task_statuses(id) ON DELETE RESTRICT,
+    parent_id INT REFERENCES tasks(id) ON DELETE CASCADE,
+    assignee_id INT,
+    project_id INT REFERENCES projects(id) ON DELETE CASCADE,
+    rank TEXT COLLATE "C" NOT NULL DEFAULT '',
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    rrule TEXT,
+    series_id INT REFERENCES task_series(id) ON DELETE SET NULL,
+    occurrence INT NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 1,
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    time_spent BIGINT NOT NULL DEFAULT 0,
+    estimate_points NUMERIC(8, 2) CHECK (estimate_points >= 0),
+    estimate_hours NUMERIC(8, 2) CHECK (estimate_hours >= 0),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    custom_fields JSONB NOT NULL DEFAULT '{}',
+    CHECK (parent_id <> id)
 );
 
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
+CREATE INDEX idx_tasks_assignee_id ON tasks(assignee_id);
+CREATE INDEX idx_tasks_project_id ON tasks(project_id);
+CREATE INDEX idx_tasks_board ON tasks(project_id, status_id, rank);
+CREATE INDEX idx_tasks_custom_fields ON tasks USING GIN (custom_fields jsonb_path_ops);
+CREATE UNIQUE INDEX idx_tasks_series_occurrence ON tasks(series_id, occurrence);
+CREATE INDEX idx_tasks_deadline ON tasks(deadline) WHERE status_id <> 3;
+CREATE INDEX idx_tasks_labels ON tasks USING GIN (labels);
+CREATE INDEX idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+
 INSERT INTO task_statuses (name, code) VALUES
     ('New', 'new'),
     ('In Progress', 'in_progress'),
     ('Completed', 'completed')
-ON CONFLICT (code) DO NOTHING;
\ No newline at end of file
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE task_dependencies (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_by ON task_dependencies(blocked_by_id);
+
+
+CREATE TABLE reminder_settings (
+    user_id INT PRIMARY KEY,
+    offsets_minutes INT[] NOT NULL
+);
+
+CREATE TABLE sent_reminders (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    offset_minutes INT NOT NULL,
+    -- NULL while the reminder is claimed by a scheduler but not yet sent
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (task_id, offset_minutes)
+);
+
+-- the notifiers a reminder went out through, so that a retry after one of
+-- them failed does not repeat the others
+CREATE TABLE sent_reminder_channels (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    offset_minutes INT NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, offset_minutes, channel)
+);
+
+CREATE TABLE task_attachments (
+    id SERIAL PRIMARY KEY,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_attachments_task_id ON task_attachments(task_id);
+
+CREATE TABLE task_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    actor_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_events_task_id ON task_events(task_id, id DESC);
+
+CREATE TABLE calendar_tokens (
+    user_id INT PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_user_id ON webhooks(user_id);
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    response_status INT,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, id DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    schema_version INT NOT NULL,
+    aggregate_id INT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_unpublished ON outbox(id) WHERE published_at IS NULL;
+
+CREATE TABLE time_entries (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    duration BIGINT GENERATED ALWAYS AS (EXTRACT(EPOCH FROM ended_at - started_at)::BIGINT) STORED,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ended_at >= started_at)
+);
+
+CREATE INDEX idx_time_entries_task_id ON time_entries(task_id, started_at);
+CREATE INDEX idx_time_entries_user_id ON time_entries(user_id, started_at);
+-- at most one running timer per user
+CREATE UNIQUE INDEX idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
+
+CREATE TABLE views (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    project_id INT REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    sort VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE INDEX idx_views_project_id ON views(project_id);
+
+CREATE TABLE project_fields (
+    id SERIAL PRIMARY KEY,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'date', 'select', 'multi_select', 'user')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TABLE templates (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    project_id INT REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    body JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE INDEX idx_templates_project_id ON templates(project_id);
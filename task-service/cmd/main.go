@@ -10,10 +10,14 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dielit66/task-management-system/internal/client"
 	"github.com/dielit66/task-management-system/internal/config"
 	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/notifier"
+	"github.com/dielit66/task-management-system/internal/publisher"
 	repository "github.com/dielit66/task-management-system/internal/repository/postgres"
 	"github.com/dielit66/task-management-system/internal/rest"
+	"github.com/dielit66/task-management-system/internal/storage"
 	"github.com/dielit66/task-management-system/internal/usecase"
 	"github.com/gorilla/mux"
 
@@ -57,14 +61,187 @@ func main() {
 	l.Info("Creating new tasks repository")
 	repo := repository.NewTaskRepository(db, l)
 
+	l.Info("Creating new projects repository")
+	projectRepo := repository.NewProjectRepository(db, l)
+
+	l.Info("Creating user-service client", "url", cfg.UserService.URL)
+	users := client.NewUserClient(cfg.UserService.URL, cfg.UserService.Timeout, cfg.UserService.Retries, cfg.UserService.Backoff, l)
+
 	l.Info("Creating new user usecase")
-	usecase := usecase.NewTaskUsecase(repo, l)
+	taskUsecase := usecase.NewTaskUsecase(repo, projectRepo, users, l, usecase.TaskUsecaseConfig{
+		RequireClosedSubtasks: cfg.Tasks.RequireClosedSubtasks,
+		RequireProject:        cfg.Tasks.RequireProject,
+		MaxBulkOperations:     cfg.Tasks.MaxBulkOperations,
+		MaxImportRows:         cfg.Tasks.MaxImportRows,
+	})
+
+	l.Info("Creating new project usecase")
+	projectUsecase := usecase.NewProjectUsecase(projectRepo, users, l)
+
+	l.Info("Creating new reminders repository")
+	reminderRepo := repository.NewReminderRepository(db, l)
+
+	var notifiers []notifier.Channel
+	for _, name := range cfg.Reminders.Notifiers {
+		var n notifier.Notifier
+		switch name {
+		case "log":
+			n = notifier.NewLogNotifier(l)
+		case "email":
+			n = notifier.NewEmailNotifier(cfg.Reminders.SMTP.Addr, cfg.Reminders.SMTP.From,
+				cfg.Reminders.SMTP.Username, cfg.Reminders.SMTP.Password, users)
+		case "webhook":
+			n = notifier.NewWebhookNotifier(cfg.Reminders.Webhook.URL, cfg.Reminders.Webhook.Timeout)
+		default:
+			l.Fatal("Unknown reminder notifier", "notifier", name)
+		}
+		notifiers = append(notifiers, notifier.Channel{Name: name, Notifier: n})
+	}
+
+	l.Info("Creating new reminder usecase")
+	reminderUsecase := usecase.NewReminderUsecase(reminderRepo, notifiers, l, usecase.ReminderUsecaseConfig{
+		DefaultOffsets: cfg.Reminders.DefaultOffsets,
+		Interval:       cfg.Reminders.Interval,
+		Lookback:       cfg.Reminders.Lookback,
+		BatchSize:      cfg.Reminders.BatchSize,
+	})
+
+	var blobs storage.BlobStore
+	switch cfg.Attachments.Storage {
+	case "local":
+		blobs, err = storage.NewLocalStore(cfg.Attachments.LocalDir)
+	case "s3":
+		blobs, err = storage.NewS3Store(cfg.Attachments.S3.Endpoint, cfg.Attachments.S3.Region, cfg.Attachments.S3.Bucket,
+			cfg.Attachments.S3.AccessKey, cfg.Attachments.S3.SecretKey, cfg.Attachments.S3.Timeout)
+	default:
+		err = fmt.Errorf("unknown storage %q", cfg.Attachments.Storage)
+	}
+	if err != nil {
+		l.Fatal("Failed to create attachment storage", "err", err.Error())
+	}
+
+	l.Info("Creating new attachment usecase", "storage", cfg.Attachments.Storage)
+	attachmentUsecase := usecase.NewAttachmentUsecase(repository.NewAttachmentRepository(db, l), repo, projectRepo, blobs, l,
+		usecase.AttachmentUsecaseConfig{
+			MaxSize:      cfg.Attachments.MaxSize,
+			AllowedTypes: cfg.Attachments.AllowedTypes,
+		})
+
+	timeUsecase := usecase.NewTimeUsecase(repository.NewTimeRepository(db, l), repo, projectRepo, l)
+
+	statsUsecase := usecase.NewStatsUsecase(repo, projectRepo, l)
+
+	viewUsecase := usecase.NewViewUsecase(repository.NewViewRepository(db, l), taskUsecase, projectRepo, l)
+
+	templateUsecase := usecase.NewTemplateUsecase(repository.NewTemplateRepository(db, l), taskUsecase, projectRepo, l)
+
+	purgeUsecase := usecase.NewPurgeUsecase(repo, blobs, l, usecase.PurgeUsecaseConfig{
+		Retention: cfg.Trash.Retention,
+		Interval:  cfg.Trash.PurgeInterval,
+		BatchSize: cfg.Trash.BatchSize,
+	})
+
+	webhookUsecase, err := usecase.NewWebhookUsecase(repository.NewWebhookRepository(db, l), l, usecase.WebhookUsecaseConfig{
+		Interval:        cfg.Webhooks.Interval,
+		BatchSize:       cfg.Webhooks.BatchSize,
+		Timeout:         cfg.Webhooks.Timeout,
+		MaxAttempts:     cfg.Webhooks.MaxAttempts,
+		MinBackoff:      cfg.Webhooks.MinBackoff,
+		MaxBackoff:      cfg.Webhooks.MaxBackoff,
+		Retention:       cfg.Webhooks.Retention,
+		AllowedNetworks: cfg.Webhooks.AllowedNetworks,
+	})
+	if err != nil {
+		l.Fatal("Failed to create webhook usecase", "err", err.Error())
+	}
+
+	streamUsecase := usecase.NewStreamUsecase(repo, repository.NewEventListener(dbDsn, l), l, usecase.StreamUsecaseConfig{
+		BufferSize:  cfg.Stream.BufferSize,
+		ReplayLimit: cfg.Stream.ReplayLimit,
+	})
+
+	collabUsecase := usecase.NewCollabUsecase(repo, projectRepo, l, usecase.CollabUsecaseConfig{
+		BufferSize: cfg.Collab.BufferSize,
+		MaxTopics:  cfg.Collab.MaxTopics,
+	})
+
+	var events publisher.EventPublisher
+	switch cfg.Outbox.Publisher {
+	case "log":
+		events = publisher.NewLogPublisher(l)
+	case "nats":
+		if cfg.Outbox.NATS.Embedded {
+			broker, err := publisher.NewEmbeddedBroker(cfg.Outbox.NATS.URL, l)
+			if err != nil {
+				l.Fatal("Failed to start embedded broker", "err", err.Error())
+			}
+			defer broker.Close()
+			l.Info("Started embedded broker", "addr", broker.Addr())
+		}
+		events, err = publisher.NewNATSPublisher(cfg.Outbox.NATS.URL, cfg.Outbox.NATS.SubjectPrefix, cfg.Outbox.NATS.Timeout)
+	default:
+		err = fmt.Errorf("unknown publisher %q", cfg.Outbox.Publisher)
+	}
+	if err != nil {
+		l.Fatal("Failed to create event publisher", "err", err.Error())
+	}
+
+	outboxUsecase := usecase.NewOutboxUsecase(repository.NewOutboxRepository(db, l), events, l, usecase.OutboxUsecaseConfig{
+		Interval:   cfg.Outbox.Interval,
+		BatchSize:  cfg.Outbox.BatchSize,
+		MinBackoff: cfg.Outbox.MinBackoff,
+		MaxBackoff: cfg.Outbox.MaxBackoff,
+		Retention:  cfg.Outbox.Retention,
+	})
+
+	calendarUsecase := usecase.NewCalendarUsecase(repository.NewCalendarRepository(db, l), taskUsecase, l)
 
 	l.Info("Creating router")
 	router := mux.NewRouter()
 
+	// The calendar feed and the WebSocket authenticate by themselves and
+	// have to be registered before the JWT protected API, which matches
+	// every path.
+	l.Info("Creating new calendar feed handler")
+	rest.NewCalendarFeedHandler(router, calendarUsecase, l)
+
+	l.Info("Creating new collaboration handler")
+	rest.NewCollabHandler(router, collabUsecase, streamUsecase, l)
+
+	api := router.NewRoute().Subrouter()
+
 	l.Info("Creating new user handler")
-	rest.NewTaskHandler(router, usecase, l)
+	rest.NewTaskHandler(api, taskUsecase, cfg.Tasks.RequireIfMatch, l)
+
+	l.Info("Creating new project handler")
+	rest.NewProjectHandler(api, projectUsecase, l)
+
+	l.Info("Creating new reminder handler")
+	rest.NewReminderHandler(api, reminderUsecase, l)
+
+	l.Info("Creating new attachment handler")
+	rest.NewAttachmentHandler(api, attachmentUsecase, cfg.Attachments.MaxSize, l)
+
+	l.Info("Creating new time tracking handler")
+	rest.NewTimeHandler(api, timeUsecase, l)
+
+	l.Info("Creating new stats handler")
+	rest.NewStatsHandler(api, statsUsecase, l)
+
+	l.Info("Creating new view handler")
+	rest.NewViewHandler(api, viewUsecase, l)
+
+	l.Info("Creating new template handler")
+	rest.NewTemplateHandler(api, templateUsecase, l)
+
+	l.Info("Creating new calendar handler")
+	rest.NewCalendarHandler(api, calendarUsecase, l)
+
+	l.Info("Creating new webhook handler")
+	rest.NewWebhookHandler(api, webhookUsecase, l)
+
+	l.Info("Creating new stream handler")
+	rest.NewStreamHandler(api, streamUsecase, cfg.Stream.Heartbeat, l)
 
 	port := fmt.Sprintf(":%s", cfg.Server.Port)
 
@@ -75,6 +252,33 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 	}
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	if cfg.Reminders.Enabled {
+		l.Info("Starting reminder scheduler", "interval", cfg.Reminders.Interval)
+		go reminderUsecase.Run(workersCtx)
+	}
+
+	l.Info("Starting trash purger", "retention", cfg.Trash.Retention, "interval", cfg.Trash.PurgeInterval)
+	go purgeUsecase.Run(workersCtx)
+
+	// Stopping the workers also ends open event streams and WebSockets,
+	// which would otherwise hold up the server shutdown.
+	l.Info("Starting task event stream")
+	go streamUsecase.Run(workersCtx)
+	go collabUsecase.Run(workersCtx)
+
+	if cfg.Webhooks.Enabled {
+		l.Info("Starting webhook dispatcher", "interval", cfg.Webhooks.Interval)
+		go webhookUsecase.Run(workersCtx)
+	}
+
+	if cfg.Outbox.Enabled {
+		l.Info("Starting outbox relay", "publisher", cfg.Outbox.Publisher, "interval", cfg.Outbox.Interval)
+		go outboxUsecase.Run(workersCtx)
+	}
+
 	go func() {
 		l.Info("Starting server", "port", port)
 		if err := srv.ListenAndServe(); err != nil {
@@ -87,10 +291,15 @@ func main() {
 
 	<-sdChan
 	l.Info("Shutting down the server...")
+	stopWorkers()
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if err := collabUsecase.Wait(shutdownCtx); err != nil {
+		l.Warn("WebSockets did not close in time", "error", err.Error())
+	}
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		l.Fatal("Server shutdown error", "error", err.Error())
 	}
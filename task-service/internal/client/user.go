@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// UserClient talks to user-service over HTTP.
+type UserClient struct {
+	baseURL string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+	logger  logger.ILogger
+}
+
+func NewUserClient(baseURL string, timeout time.Duration, retries int, backoff time.Duration, l logger.ILogger) *UserClient {
+	return &UserClient{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: timeout},
+		retries: retries,
+		backoff: backoff,
+		logger:  l,
+	}
+}
+
+// GetUser fetches a user by id. Network failures and 5xx responses are
+// retried with exponential backoff, a missing user is reported as
+// app.ErrNotFound.
+func (c *UserClient) GetUser(ctx context.Context, id int) (*entities.User, error) {
+	url := fmt.Sprintf("%s/users/%d", c.baseURL, id)
+
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff << (attempt - 1)
+			c.logger.Warn("Retrying user-service request", "user_id", id, "attempt", attempt, "error", lastErr.Error())
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		user, retry, err := c.getUser(ctx, url)
+		if err == nil {
+			return user, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, app.Wrap(lastErr, app.ErrInternal, "user-service is unavailable")
+}
+
+func (c *UserClient) getUser(ctx context.Context, url string) (*entities.User, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, app.NewAppError(app.ErrNotFound, "user not found", nil)
+	case resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("user-service responded with %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("user-service responded with %d", resp.StatusCode)
+	}
+
+	var user entities.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, false, fmt.Errorf("decode user-service response: %w", err)
+	}
+
+	return &user, false, nil
+}
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/ilyakaznacheev/cleanenv"
+import (
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
 
 type Config struct {
 	LogLevel int8 `yaml:"log_level"`
@@ -14,6 +18,95 @@ type Config struct {
 		Name     string `yaml:"name"`
 		Host     string `yaml:"host"`
 	} `yaml:"database"`
+	UserService struct {
+		URL     string        `yaml:"url" env-default:"http://user-service:8081"`
+		Timeout time.Duration `yaml:"timeout" env-default:"3s"`
+		Retries int           `yaml:"retries" env-default:"2"`
+		Backoff time.Duration `yaml:"backoff" env-default:"200ms"`
+	} `yaml:"user_service"`
+	Reminders struct {
+		Enabled        bool          `yaml:"enabled" env-default:"true"`
+		Interval       time.Duration `yaml:"interval" env-default:"1m"`
+		Lookback       time.Duration `yaml:"lookback" env-default:"24h"`
+		BatchSize      int           `yaml:"batch_size" env-default:"100"`
+		DefaultOffsets []int         `yaml:"default_offsets" env-default:"1440,60,0"`
+		Notifiers      []string      `yaml:"notifiers" env-default:"log"`
+		SMTP           struct {
+			Addr     string `yaml:"addr"`
+			From     string `yaml:"from"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+		} `yaml:"smtp"`
+		Webhook struct {
+			URL     string        `yaml:"url"`
+			Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+		} `yaml:"webhook"`
+	} `yaml:"reminders"`
+	Attachments struct {
+		MaxSize      int64    `yaml:"max_size" env-default:"10485760"`
+		AllowedTypes []string `yaml:"allowed_types"`
+		Storage      string   `yaml:"storage" env-default:"local"`
+		LocalDir     string   `yaml:"local_dir" env-default:"./data/attachments"`
+		S3           struct {
+			Endpoint  string        `yaml:"endpoint"`
+			Region    string        `yaml:"region" env-default:"us-east-1"`
+			Bucket    string        `yaml:"bucket"`
+			AccessKey string        `yaml:"access_key"`
+			SecretKey string        `yaml:"secret_key"`
+			Timeout   time.Duration `yaml:"timeout" env-default:"30s"`
+		} `yaml:"s3"`
+	} `yaml:"attachments"`
+	Trash struct {
+		Retention     time.Duration `yaml:"retention" env-default:"720h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+		BatchSize     int           `yaml:"batch_size" env-default:"100"`
+	} `yaml:"trash"`
+	Webhooks struct {
+		Enabled     bool          `yaml:"enabled" env-default:"true"`
+		Interval    time.Duration `yaml:"interval" env-default:"5s"`
+		BatchSize   int           `yaml:"batch_size" env-default:"20"`
+		Timeout     time.Duration `yaml:"timeout" env-default:"10s"`
+		MaxAttempts int           `yaml:"max_attempts" env-default:"8"`
+		MinBackoff  time.Duration `yaml:"min_backoff" env-default:"30s"`
+		MaxBackoff  time.Duration `yaml:"max_backoff" env-default:"6h"`
+		Retention   time.Duration `yaml:"retention" env-default:"168h"`
+		// AllowedNetworks lets webhooks reach these CIDRs even though
+		// they are loopback or private.
+		AllowedNetworks []string `yaml:"allowed_networks"`
+	} `yaml:"webhooks"`
+	Stream struct {
+		Heartbeat   time.Duration `yaml:"heartbeat" env-default:"15s"`
+		BufferSize  int           `yaml:"buffer_size" env-default:"64"`
+		ReplayLimit int           `yaml:"replay_limit" env-default:"500"`
+	} `yaml:"stream"`
+	Collab struct {
+		BufferSize int `yaml:"buffer_size" env-default:"64"`
+		MaxTopics  int `yaml:"max_topics" env-default:"50"`
+	} `yaml:"collab"`
+	Outbox struct {
+		Enabled    bool          `yaml:"enabled" env-default:"true"`
+		Publisher  string        `yaml:"publisher" env-default:"log"`
+		Interval   time.Duration `yaml:"interval" env-default:"1s"`
+		BatchSize  int           `yaml:"batch_size" env-default:"100"`
+		MinBackoff time.Duration `yaml:"min_backoff" env-default:"1s"`
+		MaxBackoff time.Duration `yaml:"max_backoff" env-default:"5m"`
+		Retention  time.Duration `yaml:"retention" env-default:"168h"`
+		NATS       struct {
+			URL           string        `yaml:"url" env-default:"nats://localhost:4222"`
+			SubjectPrefix string        `yaml:"subject_prefix" env-default:"tasks"`
+			Timeout       time.Duration `yaml:"timeout" env-default:"5s"`
+			// Embedded starts a local stand-in broker on URL instead of
+			// connecting to a real one.
+			Embedded bool `yaml:"embedded" env-default:"false"`
+		} `yaml:"nats"`
+	} `yaml:"outbox"`
+	Tasks struct {
+		RequireClosedSubtasks bool `yaml:"require_closed_subtasks" env-default:"true"`
+		RequireProject        bool `yaml:"require_project" env-default:"false"`
+		RequireIfMatch        bool `yaml:"require_if_match" env-default:"false"`
+		MaxBulkOperations     int  `yaml:"max_bulk_operations" env-default:"100"`
+		MaxImportRows         int  `yaml:"max_import_rows" env-default:"10000"`
+	} `yaml:"tasks"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id" db:"task_id"`
+	UserID      int       `json:"user_id" db:"user_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
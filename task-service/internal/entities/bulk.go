@@ -0,0 +1,57 @@
+package entities
+
+import "encoding/json"
+
+const (
+	BulkCreate     = "create"
+	BulkUpdate     = "update"
+	BulkTransition = "transition"
+	BulkDelete     = "delete"
+	BulkAddLabel   = "add_label"
+)
+
+const (
+	// BulkAtomic commits all operations or none of them.
+	BulkAtomic = "atomic"
+	// BulkBestEffort commits every operation that succeeds.
+	BulkBestEffort = "best_effort"
+)
+
+const (
+	BulkStatusOK         = "ok"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back"
+	BulkStatusSkipped    = "skipped"
+)
+
+// BulkOperation is one item of a bulk request. ID names the task for every
+// operation but create; a non-zero Version makes the operation conditional
+// on that version of the task.
+type BulkOperation struct {
+	Op       string                     `json:"op"`
+	ID       int                        `json:"id,omitempty"`
+	Version  int                        `json:"version,omitempty"`
+	Task     *CreateTaskDto             `json:"task,omitempty"`
+	Fields   map[string]json.RawMessage `json:"fields,omitempty"`
+	StatusID int                        `json:"status_id,omitempty"`
+	Label    string                     `json:"label,omitempty"`
+}
+
+type BulkRequest struct {
+	Mode       string          `json:"mode"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+type BulkResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	Task   *Task  `json:"task,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+type BulkResponse struct {
+	Committed bool          `json:"committed"`
+	Results   []*BulkResult `json:"results"`
+}
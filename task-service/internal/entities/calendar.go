@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// CalendarToken is the secret that grants read access to a user's
+// calendar feed. Path is where the feed is served.
+type CalendarToken struct {
+	UserID    int       `json:"-" db:"user_id"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Path      string    `json:"path" db:"-"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+// Messages exchanged over the collaboration WebSocket. Clients send
+// subscribe, unsubscribe and typing; the server answers with the rest.
+const (
+	CollabSubscribe    = "subscribe"
+	CollabUnsubscribe  = "unsubscribe"
+	CollabTyping       = "typing"
+	CollabSubscribed   = "subscribed"
+	CollabUnsubscribed = "unsubscribed"
+	CollabJoined       = "joined"
+	CollabLeft         = "left"
+	CollabEvent        = "event"
+	CollabError        = "error"
+)
+
+// CollabMessage is a message of the collaboration channel. Topics are
+// "task:<id>" and "project:<id>"; Users lists who is present on a topic
+// when subscribing to it.
+type CollabMessage struct {
+	Type   string       `json:"type"`
+	Topic  string       `json:"topic,omitempty"`
+	UserID int          `json:"user_id,omitempty"`
+	Users  []int        `json:"users,omitempty"`
+	Typing *bool        `json:"typing,omitempty"`
+	Event  *StreamEvent `json:"event,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	Code   string       `json:"code,omitempty"`
+}
+
+// CollabClient is one connection to the collaboration channel. Send is
+// closed by the hub when the client must go: CloseReason tells whether it
+// fell behind or the service is shutting down.
+type CollabClient struct {
+	UserID      int
+	Send        chan *CollabMessage
+	CloseReason string
+	Topics      map[string]bool
+}
+
+const (
+	CollabClosedSlow     = "slow"
+	CollabClosedShutdown = "shutdown"
+)
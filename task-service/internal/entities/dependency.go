@@ -0,0 +1,18 @@
+package entities
+
+// Dependency says that TaskID cannot start until BlockedByID is completed.
+type Dependency struct {
+	TaskID      int `json:"task_id" db:"task_id"`
+	BlockedByID int `json:"blocked_by_id" db:"blocked_by_id"`
+}
+
+type CreateDependencyDto struct {
+	BlockedByID int `json:"blocked_by_id"`
+}
+
+type TaskGraph struct {
+	Tasks        []*Task      `json:"tasks"`
+	Edges        []Dependency `json:"edges"`
+	Order        []int        `json:"order"`
+	CriticalPath []int        `json:"critical_path"`
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskRestored      = "task.restored"
+)
+
+// FieldChange holds the value of a task field before and after a write.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// TaskEvent is one entry of a task's history: who changed which fields
+// and when. Events outlive the task they describe.
+type TaskEvent struct {
+	ID        int                    `json:"id"`
+	TaskID    int                    `json:"task_id" db:"task_id"`
+	ActorID   int                    `json:"actor_id" db:"actor_id"`
+	Type      string                 `json:"type"`
+	Changes   map[string]FieldChange `json:"changes" db:"-"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+// TaskHistory is one page of a task's events, newest first.
+type TaskHistory struct {
+	Events []*TaskEvent `json:"events"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// untrackedFields are left out of task diffs: they are either assigned by
+// the database, derived, or only describe the position on a board.
+var untrackedFields = map[string]bool{
+	"id":           true,
+	"created_at":   true,
+	"rank":         true,
+	"version":      true,
+	"deleted_at":   true,
+	"completed_at": true,
+	"time_spent":   true,
+	"progress":     true,
+	"urgency":      true,
+	"children":     true,
+}
+
+// DiffTasks returns the fields that differ between old and new, keyed by
+// their JSON name. A nil old describes a creation, a nil new a deletion.
+func DiffTasks(old, new *Task) map[string]FieldChange {
+	before, after := taskFields(old), taskFields(new)
+
+	changes := make(map[string]FieldChange)
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name], value) {
+			changes[name] = FieldChange{Old: before[name], New: value}
+		}
+	}
+	for name, value := range before {
+		if _, ok := after[name]; !ok {
+			changes[name] = FieldChange{Old: value}
+		}
+	}
+
+	return changes
+}
+
+func taskFields(t *Task) map[string]interface{} {
+	if t == nil {
+		return nil
+	}
+
+	// Times are compared in UTC so that the same instant given with
+	// another offset is not reported as a change.
+	normalized := *t
+	normalized.Deadline = t.Deadline.UTC()
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	for name := range untrackedFields {
+		delete(fields, name)
+	}
+
+	return fields
+}
+
+// StreamEvent is a task event pushed to live clients, along with the task
+// as it is now. Viewers are the users who can see the task.
+type StreamEvent struct {
+	TaskEvent
+	Task    *Task `json:"task"`
+	Viewers []int `json:"-"`
+}
+
+// Subscription receives the stream events visible to its user. Events is
+// closed when the subscriber falls too far behind or the service shuts
+// down; the client is expected to reconnect and resume from the last event
+// it got.
+type Subscription struct {
+	UserID int
+	Events chan *StreamEvent
+}
@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	FieldText        = "text"
+	FieldNumber      = "number"
+	FieldDate        = "date"
+	FieldSelect      = "select"
+	FieldMultiSelect = "multi_select"
+	FieldUser        = "user"
+)
+
+// CustomFieldPrefix marks custom fields in the filter and sort of the
+// task list: cf.<name>.
+const CustomFieldPrefix = "cf."
+
+// fieldName is the form of custom field names.
+var fieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// ValidFieldName reports whether name can name a custom field: lower case
+// letters, digits and underscores, starting with a letter.
+func ValidFieldName(name string) bool {
+	return fieldName.MatchString(name)
+}
+
+// ProjectField defines a custom field of the project's tasks. Name is the
+// key of the value in Task.CustomFields and cannot be changed; Options
+// lists the choices of select and multi_select fields.
+type ProjectField struct {
+	ID        int            `json:"id"`
+	ProjectID int            `json:"project_id" db:"project_id"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Options   pq.StringArray `json:"options"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// CustomFields holds the custom field values of a task by field name, as
+// stored: text, select and date (YYYY-MM-DD) fields are strings, number
+// fields numbers, user fields user IDs and multi_select fields lists of
+// strings.
+type CustomFields map[string]interface{}
+
+func (f CustomFields) Value() (driver.Value, error) {
+	if f == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(map[string]interface{}(f))
+}
+
+func (f *CustomFields) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*f = CustomFields{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into custom fields", src)
+	}
+
+	fields := CustomFields{}
+	if err := json.Unmarshal(data, (*map[string]interface{})(&fields)); err != nil {
+		return err
+	}
+	*f = fields
+
+	return nil
+}
+
+// MarshalJSON writes no values as an empty object rather than null.
+func (f CustomFields) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(map[string]interface{}(f))
+}
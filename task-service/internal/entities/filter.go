@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FilterError is a task filter parameter that does not parse. Code is
+// the machine readable error code returned by the API.
+type FilterError struct {
+	Code    string
+	Message string
+}
+
+func (e *FilterError) Error() string {
+	return e.Message
+}
+
+// taskFilterParams are the parameters of the task filter grammar.
+var taskFilterParams = map[string]bool{
+	"assigned_to": true,
+	"project_id":  true,
+}
+
+// IsTaskFilterParam reports whether name is part of the task filter
+// grammar.
+func IsTaskFilterParam(name string) bool {
+	if field, ok := strings.CutPrefix(name, CustomFieldPrefix); ok {
+		return ValidFieldName(field)
+	}
+
+	return taskFilterParams[name]
+}
+
+// ParseTaskFilter reads the filter grammar shared by GET /tasks and saved
+// views for userID:
+//
+//	assigned_to  "me" or a user ID
+//	project_id   a project ID
+//	cf.<name>    a value of the custom field, or one of the values of a
+//	             multi_select field; "me" stands for userID in user fields
+//
+// Custom field values are only checked against the field once the
+// project is known. Parameters outside the grammar are ignored.
+func ParseTaskFilter(q url.Values, userID int) (TaskFilter, error) {
+	filter := TaskFilter{UserID: userID}
+
+	if assignedTo := q.Get("assigned_to"); assignedTo != "" {
+		assigneeID := userID
+		if assignedTo != "me" {
+			id, err := strconv.Atoi(assignedTo)
+			if err != nil {
+				return filter, &FilterError{Code: "invalid_assigned_to", Message: `assigned_to must be "me" or a user ID`}
+			}
+			assigneeID = id
+		}
+		filter.AssigneeID = &assigneeID
+	}
+
+	if v := q.Get("project_id"); v != "" {
+		projectID, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, &FilterError{Code: "invalid_project_id", Message: "Invalid project ID"}
+		}
+		filter.ProjectID = &projectID
+	}
+
+	for name := range q {
+		field, ok := strings.CutPrefix(name, CustomFieldPrefix)
+		if !ok {
+			continue
+		}
+		if !ValidFieldName(field) {
+			return filter, &FilterError{Code: "invalid_custom_field", Message: "Invalid custom field " + strconv.Quote(field)}
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(map[string]string)
+		}
+		filter.CustomFields[field] = q.Get(name)
+	}
+
+	return filter, nil
+}
+
+// ParseTaskSort checks a sort of the task list; empty keeps board order.
+// Besides urgency, tasks can be sorted by a custom field with cf.<name>,
+// or -cf.<name> for descending order.
+func ParseTaskSort(sort string) (string, error) {
+	switch sort {
+	case "", SortUrgency:
+		return sort, nil
+	}
+
+	if _, field, ok := SortField(sort); ok && ValidFieldName(field) {
+		return sort, nil
+	}
+
+	return "", &FilterError{Code: "invalid_sort", Message: "Unsupported sort"}
+}
+
+// SortField returns the custom field a sort orders by and whether the
+// order is descending.
+func SortField(sort string) (desc bool, field string, ok bool) {
+	desc = strings.HasPrefix(sort, "-")
+	field, ok = strings.CutPrefix(strings.TrimPrefix(sort, "-"), CustomFieldPrefix)
+	return desc, field, ok
+}
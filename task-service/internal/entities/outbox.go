@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventSchemaVersion is the version of the envelope and data of published
+// events. It changes whenever a field is removed or changes meaning;
+// adding fields is backwards compatible and keeps the version.
+const EventSchemaVersion = 1
+
+// EventSource names this service in published events.
+const EventSource = "task-service"
+
+// OutboxMessage is an event waiting in, or published from, the outbox.
+// EventID is unique per event and stays the same across redeliveries, so
+// consumers can drop duplicates. Payload is the published envelope:
+//
+//	{
+//	  "id": "<event id>",
+//	  "type": "task.created",
+//	  "schema_version": 1,
+//	  "source": "task-service",
+//	  "occurred_at": "<RFC 3339 time>",
+//	  "task_id": 1,
+//	  "actor_id": 1,
+//	  "data": {"changes": {...}, "task": {...}}
+//	}
+type OutboxMessage struct {
+	ID            int64           `json:"-"`
+	EventID       string          `json:"id" db:"event_id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version" db:"schema_version"`
+	AggregateID   int             `json:"aggregate_id" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string         `json:"last_error" db:"last_error"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at" db:"published_at"`
+}
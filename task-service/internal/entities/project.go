@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type Project struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	OwnerID     int       `json:"owner_id" db:"owner_id"`
+	Archived    bool      `json:"archived"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type ProjectMember struct {
+	ProjectID int    `json:"project_id" db:"project_id"`
+	UserID    int    `json:"user_id" db:"user_id"`
+	Role      string `json:"role"`
+}
+
+type CreateProjectDto struct {
+	OwnerID     int    `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// Reminder is a notification about a task deadline. Offset is how long
+// before the deadline it is due, negative offsets are sent after it.
+type Reminder struct {
+	TaskID   int           `json:"task_id" db:"task_id"`
+	Title    string        `json:"title"`
+	Deadline time.Time     `json:"deadline"`
+	UserID   int           `json:"user_id" db:"user_id"`
+	Offset   time.Duration `json:"-" db:"-"`
+
+	OffsetMinutes int `json:"offset_minutes" db:"offset_minutes"`
+}
+
+func (r *Reminder) Overdue(now time.Time) bool {
+	return !now.Before(r.Deadline)
+}
+
+// ReminderSettings holds the user's reminder offsets in minutes before the
+// deadline.
+type ReminderSettings struct {
+	UserID         int   `json:"user_id" db:"user_id"`
+	OffsetsMinutes []int `json:"offsets_minutes" db:"offsets_minutes"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+const (
+	ScopeThis   = "this"
+	ScopeFuture = "future"
+)
+
+// TaskSeries is the template new occurrences of a recurring task are
+// spawned from. Occurrence n falls on the rule's occurrence
+// n-StartOccurrence+1 counted from DTStart.
+type TaskSeries struct {
+	ID              int       `json:"id"`
+	UserID          int       `json:"user_id" db:"user_id"`
+	RRule           string    `json:"rrule" db:"rrule"`
+	DTStart         time.Time `json:"dtstart" db:"dtstart"`
+	StartOccurrence int       `json:"start_occurrence" db:"start_occurrence"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Priority        string    `json:"priority"`
+	AssigneeID      *int      `json:"assignee_id" db:"assignee_id"`
+	ProjectID       *int      `json:"project_id" db:"project_id"`
+	ParentID        *int      `json:"parent_id" db:"parent_id"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// StatsFilter narrows statistics down to the tasks visible to UserID,
+// optionally in one project. From and To bound the burndown, a series of
+// UTC days, and the completions averaged into the lead time.
+type StatsFilter struct {
+	UserID    int
+	ProjectID *int
+	From      time.Time
+	To        time.Time
+}
+
+type StatusCount struct {
+	StatusID int    `json:"status_id" db:"status_id"`
+	Code     string `json:"code"`
+	Count    int    `json:"count"`
+}
+
+// BurndownDay is the work left at the end of a day and what was completed
+// during it. Remaining estimates only add up the tasks that have one.
+type BurndownDay struct {
+	Date            string  `json:"date"`
+	Remaining       int     `json:"remaining"`
+	RemainingPoints float64 `json:"remaining_points" db:"remaining_points"`
+	RemainingHours  float64 `json:"remaining_hours" db:"remaining_hours"`
+	Completed       int     `json:"completed"`
+}
+
+// Stats describe the tasks as they are now, except for the lead time and
+// the burndown, which cover the filter's range. Times are in seconds; the
+// average lead time is nil when no task was completed in the range.
+type Stats struct {
+	From            time.Time      `json:"from"`
+	To              time.Time      `json:"to"`
+	Total           int            `json:"total"`
+	ByStatus        []*StatusCount `json:"by_status"`
+	Overdue         int            `json:"overdue"`
+	CompletionRate  float64        `json:"completion_rate"`
+	AverageLeadTime *float64       `json:"average_lead_time"`
+	Burndown        []*BurndownDay `json:"burndown"`
+}
@@ -1,20 +1,126 @@
 package entities
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	StatusNew        = 1
+	StatusInProgress = 2
+	StatusCompleted  = 3
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+const SortUrgency = "urgency"
 
 type Task struct {
-	ID          int       `json:"id"`
-	UserId      int       `json:"user_id" db:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	Deadline    time.Time `json:"deadline"`
-	StatusID    int       `db:"status_id" json:"status_id"`
+	ID          int            `json:"id"`
+	UserId      int            `json:"user_id" db:"user_id"`
+	ParentID    *int           `json:"parent_id" db:"parent_id"`
+	AssigneeID  *int           `json:"assignee_id" db:"assignee_id"`
+	ProjectID   *int           `json:"project_id" db:"project_id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	Deadline    time.Time      `json:"deadline"`
+	StatusID    int            `db:"status_id" json:"status_id"`
+	Rank        string         `json:"rank"`
+	Priority    string         `json:"priority"`
+	RRule       *string        `json:"rrule" db:"rrule"`
+	SeriesID    *int           `json:"series_id" db:"series_id"`
+	Occurrence  int            `json:"occurrence,omitempty"`
+	Labels      pq.StringArray `json:"labels"`
+	// Version is incremented on every write and backs the task's ETag.
+	Version int `json:"version"`
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Estimates are optional, in story points and in hours.
+	EstimatePoints *float64 `json:"estimate_points" db:"estimate_points"`
+	EstimateHours  *float64 `json:"estimate_hours" db:"estimate_hours"`
+	// CompletedAt is set when the task is completed and cleared when it is
+	// reopened.
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`
+	// TimeSpent is the total of the task's finished time entries, in
+	// seconds.
+	TimeSpent int64 `json:"time_spent" db:"time_spent"`
+	// CustomFields are the values of the fields the project defines.
+	CustomFields CustomFields `json:"custom_fields" db:"custom_fields"`
+
+	// Progress is the percentage of completed subtasks, nil for leaf tasks.
+	Progress *float64 `json:"progress,omitempty" db:"-"`
+	Urgency  *float64 `json:"urgency,omitempty" db:"-"`
+	Children []*Task  `json:"children,omitempty" db:"-"`
 }
 
 type CreateTaskDto struct {
 	UserID      int       `json:"user_id" db:"user_id"`
+	ParentID    *int      `json:"parent_id" db:"parent_id"`
+	AssigneeID  *int      `json:"assignee_id" db:"assignee_id"`
+	ProjectID   *int      `json:"project_id" db:"project_id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Deadline    time.Time `json:"deadline"`
+	Priority    string    `json:"priority"`
+	RRule       *string   `json:"rrule"`
+	Labels      []string  `json:"labels"`
+
+	EstimatePoints *float64 `json:"estimate_points"`
+	EstimateHours  *float64 `json:"estimate_hours"`
+
+	CustomFields CustomFields `json:"custom_fields"`
+}
+
+// CreateTaskTreeDto is a task to create together with its subtasks.
+type CreateTaskTreeDto struct {
+	CreateTaskDto
+	Subtasks []*CreateTaskTreeDto `json:"subtasks"`
+}
+
+// TaskPatch is a partial update of a task. Fields holds the new JSON value
+// of every field to change, null clearing it; Tests holds values fields
+// must currently have for the patch to apply. Operations are applied after
+// both, one at a time and in order.
+type TaskPatch struct {
+	Fields     map[string]json.RawMessage
+	Tests      map[string]json.RawMessage
+	Operations []TaskPatchOperation
+}
+
+// TaskPatchOperation sets Field to Value, or with Test set checks that it
+// holds Value at that point of the patch.
+type TaskPatchOperation struct {
+	Field string
+	Value json.RawMessage
+	Test  bool
+}
+
+// TaskFilter narrows down the tasks listed for UserID.
+type TaskFilter struct {
+	UserID     int
+	AssigneeID *int
+	ProjectID  *int
+	// CustomFields are the cf.<name> filters as given. The task usecase
+	// types them by the project's fields into FieldMatch, a document the
+	// custom fields of listed tasks contain.
+	CustomFields map[string]string
+	FieldMatch   CustomFields
+	Sort         string
+	// Deleted lists the tasks in the trash instead of the live ones.
+	Deleted bool
+}
+
+// MoveTaskDto places a task into a board column between two neighbours.
+type MoveTaskDto struct {
+	StatusID int  `json:"status_id"`
+	PrevID   *int `json:"prev_id"`
+	NextID   *int `json:"next_id"`
 }
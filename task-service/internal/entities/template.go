@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+// TemplateTask describes a task a template creates. Title, description,
+// labels and checklist items may contain {{name}} placeholders, filled in
+// from the variables of the instantiation. Deadline is relative to the
+// moment of instantiation: "+<n>h", "+<n>d" or "+<n>w", or empty for none.
+type TemplateTask struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Priority    string          `json:"priority"`
+	Labels      []string        `json:"labels"`
+	Checklist   []string        `json:"checklist"`
+	Deadline    string          `json:"deadline"`
+	Subtasks    []*TemplateTask `json:"subtasks"`
+}
+
+// Template is a named, reusable task tree. Like views, a template with a
+// project is shared with the project's members and creates its tasks in
+// that project.
+type Template struct {
+	ID           int    `json:"id"`
+	UserID       int    `json:"user_id" db:"user_id"`
+	ProjectID    *int   `json:"project_id" db:"project_id"`
+	Name         string `json:"name"`
+	TemplateTask `db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type TemplateDto struct {
+	ProjectID *int   `json:"project_id"`
+	Name      string `json:"name"`
+	TemplateTask
+}
+
+// InstantiateTemplateDto carries the values of the template's
+// placeholders. Start, the moment deadlines are counted from, defaults to
+// now.
+type InstantiateTemplateDto struct {
+	Variables map[string]string `json:"variables"`
+	Start     *time.Time        `json:"start"`
+}
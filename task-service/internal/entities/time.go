@@ -0,0 +1,59 @@
+package entities
+
+import "time"
+
+const (
+	GroupByDay   = "day"
+	GroupByTask  = "task"
+	GroupByLabel = "label"
+)
+
+// TimeEntry is time a user spent on a task. A running timer has no end;
+// Duration is in seconds and nil until the entry ends.
+type TimeEntry struct {
+	ID        int64      `json:"id"`
+	TaskID    int        `json:"task_id" db:"task_id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	EndedAt   *time.Time `json:"ended_at" db:"ended_at"`
+	Duration  *int64     `json:"duration"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// TimeEntryDto is a manually entered or corrected entry.
+type TimeEntryDto struct {
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Note      string    `json:"note"`
+}
+
+// TimeReportFilter selects the finished entries of UserID overlapping
+// [From, To); only the overlapping part of an entry is counted.
+type TimeReportFilter struct {
+	UserID    int
+	From      time.Time
+	To        time.Time
+	GroupBy   string
+	ProjectID *int
+}
+
+// TimeReportGroup sums up one day (UTC, YYYY-MM-DD), task or label. Time
+// on tasks without labels is reported under a null label, and time on a
+// task with several labels is counted for each of them.
+type TimeReportGroup struct {
+	Day     *string `json:"day,omitempty"`
+	TaskID  *int    `json:"task_id,omitempty" db:"task_id"`
+	Title   *string `json:"title,omitempty"`
+	Label   *string `json:"label,omitempty"`
+	Seconds int64   `json:"seconds"`
+	Entries int     `json:"entries"`
+}
+
+type TimeReport struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	GroupBy string             `json:"group_by"`
+	Total   int64              `json:"total"`
+	Groups  []*TimeReportGroup `json:"groups"`
+}
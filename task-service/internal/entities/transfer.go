@@ -0,0 +1,34 @@
+package entities
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatICS    = "ics"
+)
+
+// ImportRow is one task read from an import file. Row counts from 1 for
+// the first record; Error is set when the record could not be read.
+type ImportRow struct {
+	Row      int
+	Task     CreateTaskDto
+	StatusID int
+	Error    string
+}
+
+type ImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// ImportReport describes the outcome of an import. Nothing is committed
+// when any row fails or on a dry run.
+type ImportReport struct {
+	DryRun    bool          `json:"dry_run"`
+	Committed bool          `json:"committed"`
+	Total     int           `json:"total"`
+	Valid     int           `json:"valid"`
+	Created   []int         `json:"created"`
+	Errors    []ImportError `json:"errors"`
+}
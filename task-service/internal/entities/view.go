@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// View is a named task list: a filter in the grammar of GET /tasks and a
+// sort. A view with a project is shared with the project's members and
+// only ever lists tasks of that project. Filters are applied for the user
+// running the view, so "assigned_to": "me" means whoever looks.
+type View struct {
+	ID        int               `json:"id"`
+	UserID    int               `json:"user_id" db:"user_id"`
+	ProjectID *int              `json:"project_id" db:"project_id"`
+	Name      string            `json:"name"`
+	Filter    map[string]string `json:"filter" db:"-"`
+	Sort      string            `json:"sort"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type ViewDto struct {
+	ProjectID *int              `json:"project_id"`
+	Name      string            `json:"name"`
+	Filter    map[string]string `json:"filter"`
+	Sort      string            `json:"sort"`
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EventWebhookTest is only ever sent by the test endpoint.
+const EventWebhookTest = "webhook.test"
+
+// WebhookEvents are the event types a webhook can subscribe to.
+var WebhookEvents = []string{EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted, EventTaskRestored}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// DeliveryDead marks deliveries that ran out of attempts. They stay
+	// until redelivered by hand.
+	DeliveryDead = "dead"
+)
+
+// Webhook subscribes a URL to the events of the tasks its owner can see.
+// Secret keys the HMAC signature of every delivery.
+type Webhook struct {
+	ID        int            `json:"id"`
+	UserID    int            `json:"user_id" db:"user_id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"secret"`
+	Events    pq.StringArray `json:"events"`
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook.
+// Payload is stored as it is signed and posted.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id" db:"webhook_id"`
+	EventID        *int64          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status" db:"response_status"`
+	LastError      *string         `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at" db:"delivered_at"`
+}
+
+type WebhookDeliveries struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+	Total      int                `json:"total"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
+
+// WebhookDto is the body of webhook create and update requests. Active
+// defaults to true.
+type WebhookDto struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+func (d *WebhookDto) Webhook() *Webhook {
+	w := &Webhook{URL: d.URL, Secret: d.Secret, Events: d.Events, Active: true}
+	if d.Active != nil {
+		w.Active = *d.Active
+	}
+
+	return w
+}
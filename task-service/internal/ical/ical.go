@@ -0,0 +1,233 @@
+// Package ical writes and parses the subset of iCalendar (RFC 5545) the
+// task feed and .ics imports need.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest content line allowed before folding, in
+// octets and without the line break.
+const maxLineLength = 75
+
+// Writer writes content lines with the escaping and folding iCalendar
+// requires. The first write error is kept and returned by Err.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Prop writes a property whose value is already in iCalendar syntax.
+func (w *Writer) Prop(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text writes a TEXT property, escaping its value.
+func (w *Writer) Text(name, value string) {
+	w.Prop(name, EscapeText(value))
+}
+
+// Time writes a DATE-TIME property in UTC.
+func (w *Writer) Time(name string, t time.Time) {
+	w.Prop(name, t.UTC().Format(dateTimeUTC))
+}
+
+func (w *Writer) Err() error {
+	return w.err
+}
+
+func (w *Writer) line(l string) {
+	if w.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	for len(l) > maxLineLength {
+		// Folding must not split a UTF-8 sequence.
+		cut := maxLineLength
+		if b.Len() > 0 {
+			cut-- // continuation lines start with a space
+		}
+		for cut > 0 && !utf8.RuneStart(l[cut]) {
+			cut--
+		}
+		b.WriteString(l[:cut])
+		b.WriteString("\r\n ")
+		l = l[cut:]
+	}
+	b.WriteString(l)
+	b.WriteString("\r\n")
+
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func UnescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
+
+const (
+	dateTimeUTC   = "20060102T150405Z"
+	dateTimeLocal = "20060102T150405"
+	date          = "20060102"
+)
+
+// Property is a parsed content line.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a parsed component such as VTODO, with its properties and
+// nested components.
+type Component struct {
+	Name       string
+	Props      []Property
+	Components []*Component
+}
+
+// Get returns the first property with the given name.
+func (c *Component) Get(name string) (Property, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Property{}, false
+}
+
+// Parse reads the components of an iCalendar stream, usually a single
+// VCALENDAR.
+func Parse(r io.Reader) ([]*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*Component
+	var stack []*Component
+	for i, l := range lines {
+		prop, err := parseLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			} else {
+				roots = append(roots, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property outside of a component", i+1)
+			}
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, prop)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("component %s is not closed", stack[len(stack)-1].Name)
+	}
+
+	return roots, nil
+}
+
+// unfold joins folded lines and drops empty ones.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		l := strings.TrimSuffix(sc.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines, sc.Err()
+}
+
+// parseLine splits "NAME;PARAM=value:VALUE". Colons and semicolons inside
+// quoted parameter values do not count as separators.
+func parseLine(l string) (Property, error) {
+	quoted := false
+	sep := -1
+	for i := 0; i < len(l) && sep < 0; i++ {
+		switch l[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				sep = i
+			}
+		}
+	}
+	if sep < 0 {
+		return Property{}, errors.New("missing ':'")
+	}
+
+	head := strings.Split(l[:sep], ";")
+	prop := Property{Name: strings.ToUpper(head[0]), Value: l[sep+1:]}
+	for _, p := range head[1:] {
+		name, value, _ := strings.Cut(p, "=")
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// ParseTime reads a DATE or DATE-TIME value, honouring a TZID parameter.
+// Floating times are taken as UTC.
+func ParseTime(p Property) (time.Time, error) {
+	loc := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	for _, layout := range []string{dateTimeUTC, dateTimeLocal, date} {
+		if t, err := time.ParseInLocation(layout, p.Value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date", p.Value)
+}
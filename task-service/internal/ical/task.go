@@ -0,0 +1,178 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// Components a task can be rendered as. Calendar clients that ignore
+// VTODO, which is most of them, still show VEVENTs.
+const (
+	KindEvent = "event"
+	KindTodo  = "todo"
+)
+
+const productID = "-//task-management-system//task-service//EN"
+
+// todoStatuses maps task statuses onto VTODO statuses.
+var todoStatuses = map[int]string{
+	entities.StatusNew:        "NEEDS-ACTION",
+	entities.StatusInProgress: "IN-PROCESS",
+	entities.StatusCompleted:  "COMPLETED",
+}
+
+// eventStatuses maps task statuses onto VEVENT statuses, which only know
+// whether an event is tentative or confirmed.
+var eventStatuses = map[int]string{
+	entities.StatusNew:        "TENTATIVE",
+	entities.StatusInProgress: "CONFIRMED",
+	entities.StatusCompleted:  "CONFIRMED",
+}
+
+// priorities maps task priorities onto the 1 (highest) to 9 (lowest)
+// scale of the PRIORITY property.
+var priorities = map[string]int{
+	entities.PriorityUrgent: 1,
+	entities.PriorityHigh:   3,
+	entities.PriorityNormal: 5,
+	entities.PriorityLow:    9,
+}
+
+// TaskWriter renders tasks as a VCALENDAR, one component per task. Tasks
+// without a deadline are skipped, as they have no place on a calendar.
+type TaskWriter struct {
+	w     *Writer
+	kind  string
+	begun bool
+}
+
+func NewTaskWriter(w io.Writer, kind string) *TaskWriter {
+	return &TaskWriter{w: NewWriter(w), kind: kind}
+}
+
+func (e *TaskWriter) begin() {
+	if e.begun {
+		return
+	}
+	e.begun = true
+
+	e.w.Prop("BEGIN", "VCALENDAR")
+	e.w.Prop("VERSION", "2.0")
+	e.w.Prop("PRODID", productID)
+	e.w.Prop("CALSCALE", "GREGORIAN")
+	e.w.Text("X-WR-CALNAME", "Tasks")
+}
+
+func (e *TaskWriter) Encode(t *entities.Task) error {
+	e.begin()
+	if t.Deadline.IsZero() {
+		return e.w.Err()
+	}
+
+	component := "VEVENT"
+	if e.kind == KindTodo {
+		component = "VTODO"
+	}
+
+	e.w.Prop("BEGIN", component)
+	e.w.Text("UID", fmt.Sprintf("task-%d@task-service", t.ID))
+	// There is no modification time on tasks, so the stamp is the creation
+	// time and SEQUENCE tells clients which revision they are looking at.
+	e.w.Time("DTSTAMP", t.CreatedAt)
+	e.w.Time("CREATED", t.CreatedAt)
+	e.w.Prop("SEQUENCE", strconv.Itoa(t.Version-1))
+	e.w.Text("SUMMARY", t.Title)
+	if t.Description != "" {
+		e.w.Text("DESCRIPTION", t.Description)
+	}
+	if len(t.Labels) > 0 {
+		escaped := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			escaped[i] = EscapeText(l)
+		}
+		e.w.Prop("CATEGORIES", strings.Join(escaped, ","))
+	}
+	if p, ok := priorities[t.Priority]; ok {
+		e.w.Prop("PRIORITY", strconv.Itoa(p))
+	}
+
+	if e.kind == KindTodo {
+		e.w.Time("DUE", t.Deadline)
+		e.w.Prop("STATUS", todoStatuses[t.StatusID])
+		if t.StatusID == entities.StatusCompleted {
+			e.w.Prop("PERCENT-COMPLETE", "100")
+		}
+	} else {
+		// A DATE-TIME start without an end is an event of no duration.
+		e.w.Time("DTSTART", t.Deadline)
+		e.w.Prop("STATUS", eventStatuses[t.StatusID])
+		e.w.Prop("TRANSP", "TRANSPARENT")
+	}
+	e.w.Prop("END", component)
+
+	return e.w.Err()
+}
+
+func (e *TaskWriter) Close() error {
+	e.begin()
+	e.w.Prop("END", "VCALENDAR")
+	return e.w.Err()
+}
+
+// StatusID maps a VTODO or VEVENT status back onto a task status. Unknown
+// statuses map to 0.
+func StatusID(status string) int {
+	switch strings.ToUpper(status) {
+	case "NEEDS-ACTION", "TENTATIVE":
+		return entities.StatusNew
+	case "IN-PROCESS":
+		return entities.StatusInProgress
+	case "COMPLETED":
+		return entities.StatusCompleted
+	}
+
+	return 0
+}
+
+// Priority maps a PRIORITY value onto a task priority. 0, which means
+// undefined, maps to the empty string.
+func Priority(value int) string {
+	switch {
+	case value <= 0:
+		return ""
+	case value <= 2:
+		return entities.PriorityUrgent
+	case value <= 4:
+		return entities.PriorityHigh
+	case value == 5:
+		return entities.PriorityNormal
+	}
+
+	return entities.PriorityLow
+}
+
+// SplitList splits a comma separated TEXT list such as CATEGORIES,
+// ignoring escaped commas.
+func SplitList(value string) []string {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			b.WriteByte(value[i])
+			b.WriteByte(value[i+1])
+			i++
+		case value[i] == ',':
+			items = append(items, UnescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+
+	return append(items, UnescapeText(b.String()))
+}
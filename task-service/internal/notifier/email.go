@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+type UserClient interface {
+	GetUser(ctx context.Context, id int) (*entities.User, error)
+}
+
+// EmailNotifier mails reminders to the user's address known to
+// user-service.
+type EmailNotifier struct {
+	addr  string
+	from  string
+	auth  smtp.Auth
+	users UserClient
+}
+
+// NewEmailNotifier creates a notifier sending through the SMTP server at
+// addr. Authentication is skipped when username is empty, which is what
+// local stand-ins such as MailHog expect.
+func NewEmailNotifier(addr, from, username, password string, users UserClient) *EmailNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &EmailNotifier{
+		addr:  addr,
+		from:  from,
+		auth:  auth,
+		users: users,
+	}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, r *entities.Reminder) error {
+	user, err := n.users.GetUser(ctx, r.UserID)
+	if err != nil {
+		return fmt.Errorf("lookup recipient: %w", err)
+	}
+
+	subject := fmt.Sprintf("Task %q is due %s", r.Title, r.Deadline.Format(time.RFC1123))
+	if r.Overdue(time.Now()) {
+		subject = fmt.Sprintf("Task %q is overdue", r.Title)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + user.Email,
+		"Subject: " + subject,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Task #%d %q has its deadline at %s.", r.TaskID, r.Title, r.Deadline.Format(time.RFC1123)),
+	}, "\r\n")
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{user.Email}, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
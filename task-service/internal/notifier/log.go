@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// LogNotifier writes reminders to the service log.
+type LogNotifier struct {
+	logger logger.ILogger
+}
+
+func NewLogNotifier(l logger.ILogger) *LogNotifier {
+	return &LogNotifier{logger: l}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, r *entities.Reminder) error {
+	n.logger.Info("Task deadline reminder", "task_id", r.TaskID, "user_id", r.UserID, "title", r.Title,
+		"deadline", r.Deadline, "overdue", r.Overdue(time.Now()))
+	return nil
+}
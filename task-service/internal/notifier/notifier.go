@@ -0,0 +1,19 @@
+// Package notifier delivers task deadline reminders.
+package notifier
+
+import (
+	"context"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, r *entities.Reminder) error
+}
+
+// Channel is a notifier along with the name its deliveries are recorded
+// under, so that a reminder is not sent twice through the same channel.
+type Channel struct {
+	Name string
+	Notifier
+}
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// WebhookNotifier posts reminders as JSON to a fixed URL.
+type WebhookNotifier struct {
+	url  string
+	http *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:  url,
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookPayload struct {
+	*entities.Reminder
+	Overdue bool `json:"overdue"`
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, r *entities.Reminder) error {
+	body, err := json.Marshal(webhookPayload{Reminder: r, Overdue: r.Overdue(time.Now())})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package publisher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// maxEmbeddedPayload bounds a single message on the embedded broker.
+const maxEmbeddedPayload = 1 << 20
+
+// EmbeddedBroker is a minimal in-process server for the NATS client
+// protocol, so that the NATS publisher can be run and consumed locally
+// without a real server. It knows PUB, HPUB, SUB, UNSUB and PING, matches
+// "*" and ">" wildcards, and keeps nothing: messages without subscribers
+// are dropped. Queue groups, auth and JetStream are not supported.
+type EmbeddedBroker struct {
+	listener net.Listener
+	logger   logger.ILogger
+
+	mu      sync.Mutex
+	clients map[*embeddedClient]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type embeddedClient struct {
+	conn net.Conn
+	mu   sync.Mutex
+	subs map[string]string // sid -> subject
+}
+
+// NewEmbeddedBroker listens on the host and port of a nats:// URL or a
+// bare address.
+func NewEmbeddedBroker(rawURL string, l logger.ILogger) (*EmbeddedBroker, error) {
+	addr, err := natsAddr(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &EmbeddedBroker{
+		listener: listener,
+		logger:   l,
+		clients:  make(map[*embeddedClient]struct{}),
+	}
+
+	b.wg.Add(1)
+	go b.accept()
+
+	return b, nil
+}
+
+// Addr is the address the broker listens on.
+func (b *EmbeddedBroker) Addr() string {
+	return b.listener.Addr().String()
+}
+
+// Close stops the broker and disconnects all clients.
+func (b *EmbeddedBroker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	for c := range b.clients {
+		c.conn.Close()
+	}
+	b.mu.Unlock()
+
+	err := b.listener.Close()
+	b.wg.Wait()
+	return err
+}
+
+func (b *EmbeddedBroker) accept() {
+	defer b.wg.Done()
+
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &embeddedClient{conn: conn, subs: make(map[string]string)}
+
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			conn.Close()
+			return
+		}
+		b.clients[c] = struct{}{}
+		b.mu.Unlock()
+
+		b.wg.Add(1)
+		go b.serve(c)
+	}
+}
+
+func (b *EmbeddedBroker) serve(c *embeddedClient) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		delete(b.clients, c)
+		b.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	info, _ := json.Marshal(map[string]interface{}{
+		"server_id":   "embedded",
+		"server_name": "embedded",
+		"version":     "2.10.0",
+		"proto":       1,
+		"headers":     true,
+		"max_payload": maxEmbeddedPayload,
+	})
+	if err := c.write(fmt.Sprintf("INFO %s\r\n", info)); err != nil {
+		return
+	}
+
+	br := bufio.NewReader(c.conn)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if err := b.handle(c, br, line); err != nil {
+			c.write(fmt.Sprintf("-ERR '%s'\r\n", err.Error()))
+			return
+		}
+	}
+}
+
+func (b *EmbeddedBroker) handle(c *embeddedClient, br *bufio.Reader, line string) error {
+	op, args, _ := strings.Cut(line, " ")
+	fields := strings.Fields(args)
+
+	switch strings.ToUpper(op) {
+	case "":
+		return nil
+	case "CONNECT", "PONG":
+		return nil
+	case "PING":
+		return c.write("PONG\r\n")
+	case "PUB":
+		if len(fields) != 2 && len(fields) != 3 {
+			return fmt.Errorf("invalid PUB")
+		}
+		reply := ""
+		if len(fields) == 3 {
+			reply = fields[1]
+		}
+		size, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return fmt.Errorf("invalid PUB size")
+		}
+		payload, err := readPayload(br, size)
+		if err != nil {
+			return err
+		}
+		b.route(fields[0], reply, 0, payload)
+		return nil
+	case "HPUB":
+		if len(fields) != 3 && len(fields) != 4 {
+			return fmt.Errorf("invalid HPUB")
+		}
+		reply := ""
+		if len(fields) == 4 {
+			reply = fields[1]
+		}
+		headerSize, err := strconv.Atoi(fields[len(fields)-2])
+		if err != nil {
+			return fmt.Errorf("invalid HPUB header size")
+		}
+		size, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || headerSize > size {
+			return fmt.Errorf("invalid HPUB size")
+		}
+		payload, err := readPayload(br, size)
+		if err != nil {
+			return err
+		}
+		b.route(fields[0], reply, headerSize, payload)
+		return nil
+	case "SUB":
+		if len(fields) != 2 && len(fields) != 3 {
+			return fmt.Errorf("invalid SUB")
+		}
+		c.mu.Lock()
+		c.subs[fields[len(fields)-1]] = fields[0]
+		c.mu.Unlock()
+		return nil
+	case "UNSUB":
+		if len(fields) == 0 {
+			return fmt.Errorf("invalid UNSUB")
+		}
+		c.mu.Lock()
+		delete(c.subs, fields[0])
+		c.mu.Unlock()
+		return nil
+	}
+
+	return fmt.Errorf("unknown protocol operation")
+}
+
+func readPayload(br *bufio.Reader, size int) ([]byte, error) {
+	if size < 0 || size > maxEmbeddedPayload {
+		return nil, fmt.Errorf("maximum payload exceeded")
+	}
+
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return nil, err
+	}
+	if string(payload[size:]) != "\r\n" {
+		return nil, fmt.Errorf("payload not terminated")
+	}
+
+	return payload[:size], nil
+}
+
+// route sends the message to every matching subscription. Headers, when
+// headerSize is set, are the start of payload.
+func (b *EmbeddedBroker) route(subject, reply string, headerSize int, payload []byte) {
+	b.mu.Lock()
+	clients := make([]*embeddedClient, 0, len(b.clients))
+	for c := range b.clients {
+		clients = append(clients, c)
+	}
+	b.mu.Unlock()
+
+	if reply != "" {
+		reply = " " + reply
+	}
+
+	for _, c := range clients {
+		c.mu.Lock()
+		var sids []string
+		for sid, pattern := range c.subs {
+			if subjectMatches(pattern, subject) {
+				sids = append(sids, sid)
+			}
+		}
+		c.mu.Unlock()
+
+		for _, sid := range sids {
+			var head string
+			if headerSize > 0 {
+				head = fmt.Sprintf("HMSG %s %s%s %d %d\r\n", subject, sid, reply, headerSize, len(payload))
+			} else {
+				head = fmt.Sprintf("MSG %s %s%s %d\r\n", subject, sid, reply, len(payload))
+			}
+			if err := c.write(head + string(payload) + "\r\n"); err != nil {
+				b.logger.Debug("Embedded broker dropped a message", "subject", subject, "error", err.Error())
+			}
+		}
+	}
+}
+
+func (c *embeddedClient) write(s string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := io.WriteString(c.conn, s)
+	return err
+}
+
+// subjectMatches reports whether subject matches pattern, where "*" stands
+// for one token and a trailing ">" for one or more.
+func subjectMatches(pattern, subject string) bool {
+	p := strings.Split(pattern, ".")
+	s := strings.Split(subject, ".")
+
+	for i, token := range p {
+		if token == ">" {
+			return i == len(p)-1 && len(s) > i
+		}
+		if i >= len(s) || (token != "*" && token != s[i]) {
+			return false
+		}
+	}
+
+	return len(p) == len(s)
+}
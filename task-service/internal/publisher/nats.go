@@ -0,0 +1,196 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// msgIDHeader is the header JetStream deduplicates published messages by.
+const msgIDHeader = "Nats-Msg-Id"
+
+// NATSPublisher publishes events to a NATS server, or anything speaking
+// its client protocol, on "<prefix>.<event type>". Every publish is
+// followed by a PING so that it only succeeds once the server has read
+// the message. The connection is dialled lazily and again after errors.
+type NATSPublisher struct {
+	addr    string
+	prefix  string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// NewNATSPublisher accepts nats://host:port URLs as well as bare addresses.
+func NewNATSPublisher(rawURL, prefix string, timeout time.Duration) (*NATSPublisher, error) {
+	addr, err := natsAddr(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSPublisher{addr: addr, prefix: prefix, timeout: timeout}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, m *entities.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := p.publish(ctx, m); err != nil {
+		p.conn.Close()
+		p.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return err
+	}
+
+	p.conn = conn
+	p.br = bufio.NewReader(conn)
+	p.setDeadline(ctx)
+
+	line, err := p.readLine()
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		p.conn = nil
+		return fmt.Errorf("nats: unexpected greeting %q: %v", line, err)
+	}
+
+	options, _ := json.Marshal(map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"headers":  true,
+		"name":     entities.EventSource,
+		"lang":     "go",
+	})
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", options); err != nil {
+		conn.Close()
+		p.conn = nil
+		return err
+	}
+
+	if err := p.awaitPong(); err != nil {
+		conn.Close()
+		p.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+func (p *NATSPublisher) publish(ctx context.Context, m *entities.OutboxMessage) error {
+	p.setDeadline(ctx)
+
+	headers := fmt.Sprintf("NATS/1.0\r\n%s: %s\r\nNats-Schema-Version: %d\r\n\r\n", msgIDHeader, m.EventID, m.SchemaVersion)
+	subject := p.prefix + "." + m.Type
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "HPUB %s %d %d\r\n", subject, len(headers), len(headers)+len(m.Payload))
+	b.WriteString(headers)
+	b.Write(m.Payload)
+	b.WriteString("\r\nPING\r\n")
+
+	if _, err := p.conn.Write([]byte(b.String())); err != nil {
+		return err
+	}
+
+	return p.awaitPong()
+}
+
+// awaitPong reads until the PONG answering our PING, answering the
+// server's own PINGs on the way.
+func (p *NATSPublisher) awaitPong() error {
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("nats: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		default:
+			return fmt.Errorf("nats: unexpected %q", line)
+		}
+	}
+}
+
+func (p *NATSPublisher) readLine() (string, error) {
+	line, err := p.br.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (p *NATSPublisher) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(p.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	p.conn.SetDeadline(deadline)
+}
+
+// natsAddr returns host:port of a nats:// URL or a bare address.
+func natsAddr(rawURL string) (string, error) {
+	addr := rawURL
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme != "nats" {
+			return "", fmt.Errorf("unsupported NATS URL scheme %q", u.Scheme)
+		}
+		addr = u.Host
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", fmt.Errorf("invalid NATS address %q: %w", rawURL, err)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,36 @@
+// Package publisher hands domain events from the outbox to a message
+// broker.
+package publisher
+
+import (
+	"context"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// EventPublisher publishes one outbox message. Returning nil means the
+// broker has accepted the message.
+type EventPublisher interface {
+	Publish(ctx context.Context, m *entities.OutboxMessage) error
+	Close() error
+}
+
+// LogPublisher only logs events, for setups without a broker.
+type LogPublisher struct {
+	logger logger.ILogger
+}
+
+func NewLogPublisher(l logger.ILogger) *LogPublisher {
+	return &LogPublisher{logger: l}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, m *entities.OutboxMessage) error {
+	p.logger.Info("Event published", "event_id", m.EventID, "type", m.Type, "task_id", m.AggregateID,
+		"payload", string(m.Payload))
+	return nil
+}
+
+func (p *LogPublisher) Close() error {
+	return nil
+}
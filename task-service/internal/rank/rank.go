@@ -0,0 +1,75 @@
+// Package rank generates lexicographically ordered strings used to keep a
+// user-defined order of tasks without renumbering neighbours on every move.
+package rank
+
+import "strings"
+
+const (
+	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	base   = len(digits)
+)
+
+// Between returns a rank strictly between a and b, where an empty a means
+// "before everything" and an empty b means "after everything". a must sort
+// before b. Generated ranks never end in '0' so that there is always room to
+// insert before them.
+func Between(a, b string) string {
+	var out []byte
+	upperOpen := b == ""
+
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(a) {
+			lo = strings.IndexByte(digits, a[i])
+		}
+
+		hi := base
+		if !upperOpen {
+			hi = 0
+			if i < len(b) {
+				hi = strings.IndexByte(digits, b[i])
+			}
+		}
+
+		if hi-lo > 1 {
+			return string(append(out, digits[(lo+hi)/2]))
+		}
+
+		out = append(out, digits[lo])
+		if hi > lo {
+			upperOpen = true
+		}
+	}
+}
+
+// Spread returns n evenly spaced ranks of equal length, used to rebalance a
+// column whose ranks have grown too long.
+func Spread(n int) []string {
+	width, space := 1, base
+	for space < 2*(n+1) {
+		width++
+		space *= base
+	}
+
+	step := space / (n + 1)
+	ranks := make([]string, n)
+	for i := range ranks {
+		v := step * (i + 1)
+		if v%base == 0 {
+			v++
+		}
+		ranks[i] = format(v, width)
+	}
+
+	return ranks
+}
+
+func format(v, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[v%base]
+		v /= base
+	}
+
+	return string(buf)
+}
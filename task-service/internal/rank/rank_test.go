@@ -0,0 +1,87 @@
+package rank
+
+import (
+	"strings"
+	"testing"
+)
+
+func checkBetween(t *testing.T, a, b string) string {
+	t.Helper()
+
+	r := Between(a, b)
+	if r <= a || b != "" && r >= b {
+		t.Fatalf("Between(%q, %q) = %q, want a rank strictly between them", a, b, r)
+	}
+	if strings.HasSuffix(r, "0") {
+		t.Fatalf("Between(%q, %q) = %q, which leaves no room before it", a, b, r)
+	}
+
+	return r
+}
+
+func TestBetween(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "i"},
+		{"i", ""},
+		{"1", "2"},
+		{"1", "1i"},
+		{"h", "i"},
+		{"hz", "i"},
+		{"", "01"},
+		{"zz", ""},
+		{"a0001", "a001"},
+	}
+	for _, c := range cases {
+		checkBetween(t, c[0], c[1])
+	}
+}
+
+func TestBetweenRepeatedInserts(t *testing.T) {
+	// Inserting again and again at the same spot must keep finding room.
+	first, last := checkBetween(t, "", ""), checkBetween(t, "", "")
+	for i := 0; i < 200; i++ {
+		first = checkBetween(t, "", first)
+		last = checkBetween(t, last, "")
+	}
+
+	a, b := "h", "i"
+	for i := 0; i < 200; i++ {
+		mid := checkBetween(t, a, b)
+		if i%2 == 0 {
+			a = mid
+		} else {
+			b = mid
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 17, 35, 36, 1000} {
+		ranks := Spread(n)
+		if len(ranks) != n {
+			t.Fatalf("Spread(%d) returned %d ranks", n, len(ranks))
+		}
+
+		for i, r := range ranks {
+			if len(r) != len(ranks[0]) {
+				t.Fatalf("Spread(%d)[%d] = %q, want the length of %q", n, i, r, ranks[0])
+			}
+			if strings.HasSuffix(r, "0") {
+				t.Fatalf("Spread(%d)[%d] = %q, which leaves no room before it", n, i, r)
+			}
+			if i > 0 && r <= ranks[i-1] {
+				t.Fatalf("Spread(%d)[%d] = %q does not sort after %q", n, i, r, ranks[i-1])
+			}
+		}
+
+		// There is room before, between and after the spread ranks.
+		if n > 0 {
+			checkBetween(t, "", ranks[0])
+			checkBetween(t, ranks[n-1], "")
+		}
+		for i := 1; i < n; i++ {
+			checkBetween(t, ranks[i-1], ranks[i])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type AttachmentRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewAttachmentRepository(db *sqlx.DB, l logger.ILogger) *AttachmentRepository {
+	return &AttachmentRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, a *entities.Attachment) error {
+	query := `INSERT INTO task_attachments (task_id, user_id, file_name, content_type, size, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	err := r.db.QueryRowxContext(ctx, query, a.TaskID, a.UserID, a.FileName, a.ContentType, a.Size, a.StorageKey).
+		Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *AttachmentRepository) GetById(ctx context.Context, taskID, id int) (*entities.Attachment, error) {
+	query := "SELECT * FROM task_attachments WHERE task_id = $1 AND id = $2"
+	attachment := entities.Attachment{}
+	err := r.db.GetContext(ctx, &attachment, query, taskID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("attachment %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) GetAllByTaskId(ctx context.Context, taskID int) ([]*entities.Attachment, error) {
+	query := "SELECT * FROM task_attachments WHERE task_id = $1 ORDER BY id"
+	attachments := []*entities.Attachment{}
+	err := r.db.SelectContext(ctx, &attachments, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, taskID, id int) error {
+	query := "DELETE FROM task_attachments WHERE task_id = $1 AND id = $2"
+	result, err := r.db.ExecContext(ctx, query, taskID, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("attachment %d not found", id), nil)
+	}
+
+	return nil
+}
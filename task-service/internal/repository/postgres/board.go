@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/rank"
+)
+
+// maxRankLength is the rank length after which a board column gets its
+// ranks spread out evenly again.
+const maxRankLength = 12
+
+type columnEntry struct {
+	ID   int    `db:"id"`
+	Rank string `db:"rank"`
+}
+
+// boardColumn identifies a board column: a status within a project, or
+// within the user's own tasks for tasks that have no project. The key is
+// the project id, or the negated user id for the user's own tasks.
+type boardColumn struct {
+	key    int
+	status int
+}
+
+func columnOf(projectID *int, userID, statusID int) boardColumn {
+	if projectID != nil {
+		return boardColumn{key: *projectID, status: statusID}
+	}
+
+	return boardColumn{key: -userID, status: statusID}
+}
+
+// lockBoard serializes the ranking within the columns until the
+// transaction ends. Locking the rows of a column alone does not keep two
+// transactions from ranking a task into the same gap, since neither sees
+// the task the other one adds. The locks are always taken in the same
+// order so that tasks moving between two columns in opposite directions
+// cannot deadlock. Their two-key form never collides with the single-key
+// locks used elsewhere.
+func lockBoard(ctx context.Context, tx queryer, columns ...boardColumn) error {
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i].key != columns[j].key {
+			return columns[i].key < columns[j].key
+		}
+		return columns[i].status < columns[j].status
+	})
+
+	for i, c := range columns {
+		if i > 0 && c == columns[i-1] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1, $2)", c.key, c.status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockColumn locks and returns the tasks of a board column ordered by rank.
+func lockColumn(ctx context.Context, tx queryer, projectID *int, userID, statusID int) ([]columnEntry, error) {
+	if err := lockBoard(ctx, tx, columnOf(projectID, userID, statusID)); err != nil {
+		return nil, err
+	}
+
+	var query string
+	var args []interface{}
+	if projectID != nil {
+		query = "SELECT id, rank FROM tasks WHERE project_id = $1 AND status_id = $2 AND deleted_at IS NULL ORDER BY rank, id FOR UPDATE"
+		args = []interface{}{*projectID, statusID}
+	} else {
+		query = "SELECT id, rank FROM tasks WHERE project_id IS NULL AND user_id = $1 AND status_id = $2 AND deleted_at IS NULL ORDER BY rank, id FOR UPDATE"
+		args = []interface{}{userID, statusID}
+	}
+
+	var column []columnEntry
+	if err := tx.SelectContext(ctx, &column, query, args...); err != nil {
+		return nil, err
+	}
+
+	return column, nil
+}
+
+// getForColumn locks task id for a write that puts it into the column
+// target returns for the stored task. When that is another column, both
+// are locked with lockBoard before the task itself, as every write changing
+// the column of a task does; it then reports that the task is moving.
+func getForColumn(ctx context.Context, tx queryer, id int, target func(current *entities.Task) boardColumn) (*entities.Task, bool, error) {
+	before := entities.Task{}
+	err := tx.GetContext(ctx, &before, "SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, app.NewAppError(app.ErrNotFound, fmt.Sprintf("no task found with id %d", id), err)
+		}
+		return nil, false, err
+	}
+
+	source := columnOf(before.ProjectID, before.UserId, before.StatusID)
+	dest := target(&before)
+	moving := source != dest
+	if moving {
+		if err := lockBoard(ctx, tx, source, dest); err != nil {
+			return nil, false, err
+		}
+	}
+
+	current, err := getForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The columns locked above are only right while the task stays where
+	// it was read.
+	if columnOf(current.ProjectID, current.UserId, current.StatusID) != source || target(current) != dest {
+		return nil, false, app.NewAppError(app.ErrConflict, fmt.Sprintf("task %d was moved concurrently, try again", id), nil)
+	}
+
+	return current, moving, nil
+}
+
+// bottomRank returns a rank at the bottom of the column the task is moving
+// into.
+func bottomRank(ctx context.Context, tx queryer, t *entities.Task) (string, error) {
+	column, err := lockColumn(ctx, tx, t.ProjectID, t.UserId, t.StatusID)
+	if err != nil {
+		return "", err
+	}
+
+	return rankAt(ctx, tx, column, len(column))
+}
+
+// rankAt returns a rank for a task inserted at position pos of the column,
+// rebalancing the whole column first when ranks have become too dense.
+func rankAt(ctx context.Context, tx queryer, column []columnEntry, pos int) (string, error) {
+	var lower, upper string
+	if pos > 0 {
+		lower = column[pos-1].Rank
+	}
+	if pos < len(column) {
+		upper = column[pos].Rank
+	}
+
+	if lower < upper || upper == "" {
+		if r := rank.Between(lower, upper); len(r) <= maxRankLength {
+			return r, nil
+		}
+	}
+
+	ranks := rank.Spread(len(column) + 1)
+	for i, entry := range column {
+		r := ranks[i]
+		if i >= pos {
+			r = ranks[i+1]
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET rank = $1 WHERE id = $2", r, entry.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return ranks[pos], nil
+}
+
+// Move places the task in the given status column right after prevID, or
+// right before nextID when prevID is not set, or at the end of the column
+// when neither is set. A non-zero version must match the stored one.
+func (r *TaskRepository) Move(ctx context.Context, t *entities.Task, statusID int, prevID, nextID *int, version, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, _, err := getForColumn(ctx, tx, t.ID, func(current *entities.Task) boardColumn {
+		return columnOf(current.ProjectID, current.UserId, statusID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(current, version); err != nil {
+		return err
+	}
+
+	column, err := lockColumn(ctx, tx, current.ProjectID, current.UserId, statusID)
+	if err != nil {
+		return err
+	}
+
+	others := column[:0:0]
+	for _, entry := range column {
+		if entry.ID != t.ID {
+			others = append(others, entry)
+		}
+	}
+
+	pos := len(others)
+	switch {
+	case prevID != nil:
+		pos = indexOf(others, *prevID)
+		if pos < 0 {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("task %d is not in the target column", *prevID), nil)
+		}
+		pos++
+	case nextID != nil:
+		pos = indexOf(others, *nextID)
+		if pos < 0 {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("task %d is not in the target column", *nextID), nil)
+		}
+	}
+
+	newRank, err := rankAt(ctx, tx, others, pos)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE tasks SET status_id = $1, rank = $2, " + setCompletedAt("$1") + ", version = version + 1 WHERE id = $3 RETURNING completed_at"
+	var completedAt *time.Time
+	if err := tx.GetContext(ctx, &completedAt, query, statusID, newRank, t.ID); err != nil {
+		return err
+	}
+
+	moved := *current
+	moved.StatusID, moved.Rank = statusID, newRank
+	if err := recordChanges(ctx, tx, current, &moved, actorID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	*t = moved
+	t.CompletedAt = completedAt
+	t.Version = current.Version + 1
+
+	return nil
+}
+
+func indexOf(column []columnEntry, id int) int {
+	for i, entry := range column {
+		if entry.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalendarRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewCalendarRepository(db *sqlx.DB, l logger.ILogger) *CalendarRepository {
+	return &CalendarRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+// GetToken returns the user's feed token, nil when none was issued yet.
+func (r *CalendarRepository) GetToken(ctx context.Context, userID int) (*entities.CalendarToken, error) {
+	var t entities.CalendarToken
+	err := r.db.GetContext(ctx, &t, "SELECT user_id, token, created_at FROM calendar_tokens WHERE user_id = $1", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// SaveToken stores t as the user's only token, invalidating any previous
+// one.
+func (r *CalendarRepository) SaveToken(ctx context.Context, t *entities.CalendarToken) error {
+	query := `INSERT INTO calendar_tokens (user_id, token) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+		RETURNING created_at`
+	return r.db.QueryRowxContext(ctx, query, t.UserID, t.Token).Scan(&t.CreatedAt)
+}
+
+func (r *CalendarRepository) GetUserID(ctx context.Context, token string) (int, error) {
+	var userID int
+	err := r.db.GetContext(ctx, &userID, "SELECT user_id FROM calendar_tokens WHERE token = $1", token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, app.NewAppError(app.ErrNotFound, "unknown calendar token", err)
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/lib/pq"
+)
+
+// dependencyLockKey is the advisory lock serializing the cycle checks of
+// new dependencies.
+const dependencyLockKey = 7_340_102
+
+// LockDependencies keeps other transactions from adding dependencies until
+// the current one ends. Without it two links checked at the same time can
+// together close a cycle neither of them sees.
+func (r *TaskRepository) LockDependencies(ctx context.Context) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", dependencyLockKey)
+	return err
+}
+
+func (r *TaskRepository) AddDependency(ctx context.Context, d entities.Dependency) error {
+	query := "INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, d.TaskID, d.BlockedByID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) RemoveDependency(ctx context.Context, d entities.Dependency) error {
+	query := "DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2"
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, d.TaskID, d.BlockedByID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("task %d is not blocked by %d", d.TaskID, d.BlockedByID), nil)
+	}
+
+	return nil
+}
+
+// GetDependencyComponent returns every dependency edge reachable from the
+// task, following links in both directions.
+func (r *TaskRepository) GetDependencyComponent(ctx context.Context, id int) ([]entities.Dependency, error) {
+	query := `WITH RECURSIVE live AS (
+			SELECT d.task_id, d.blocked_by_id FROM task_dependencies d
+			JOIN tasks a ON a.id = d.task_id AND a.deleted_at IS NULL
+			JOIN tasks b ON b.id = d.blocked_by_id AND b.deleted_at IS NULL
+		), component(id) AS (
+			SELECT $1::int
+			UNION
+			SELECT CASE WHEN d.task_id = c.id THEN d.blocked_by_id ELSE d.task_id END
+			FROM live d JOIN component c ON d.task_id = c.id OR d.blocked_by_id = c.id
+		)
+		SELECT d.task_id, d.blocked_by_id FROM live d
+		JOIN component c ON d.task_id = c.id
+		ORDER BY d.task_id, d.blocked_by_id`
+	var deps []entities.Dependency
+	err := conn(ctx, r.db).SelectContext(ctx, &deps, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// CountOpenBlockers returns how many tasks blocking the given one are not
+// completed yet.
+func (r *TaskRepository) CountOpenBlockers(ctx context.Context, id int) (int, error) {
+	query := `SELECT COUNT(*) FROM task_dependencies d
+		JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = $1 AND t.status_id <> $2 AND t.deleted_at IS NULL`
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, id, entities.StatusCompleted)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *TaskRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.Task, error) {
+	query := "SELECT * FROM tasks WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id"
+	var tasks []*entities.Task
+	err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/lib/pq"
+)
+
+func (r *ProjectRepository) GetFields(ctx context.Context, projectID int) ([]*entities.ProjectField, error) {
+	query := "SELECT * FROM project_fields WHERE project_id = $1 ORDER BY id"
+	var fields []*entities.ProjectField
+	err := r.db.SelectContext(ctx, &fields, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func (r *ProjectRepository) GetField(ctx context.Context, projectID, id int) (*entities.ProjectField, error) {
+	query := "SELECT * FROM project_fields WHERE id = $1 AND project_id = $2"
+	field := entities.ProjectField{}
+	err := r.db.GetContext(ctx, &field, query, id, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("field %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &field, nil
+}
+
+func (r *ProjectRepository) CreateField(ctx context.Context, f *entities.ProjectField) error {
+	query := `INSERT INTO project_fields (project_id, name, type, options) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	err := r.db.QueryRowxContext(ctx, query, f.ProjectID, f.Name, f.Type, f.Options).Scan(&f.ID, &f.CreatedAt)
+	if err != nil {
+		return fieldConflict(err, f.Name)
+	}
+
+	return nil
+}
+
+// UpdateField stores the field's options; name and type are fixed once
+// the field exists.
+func (r *ProjectRepository) UpdateField(ctx context.Context, f *entities.ProjectField) error {
+	query := "UPDATE project_fields SET options = $1 WHERE id = $2 AND project_id = $3"
+	result, err := r.db.ExecContext(ctx, query, f.Options, f.ID, f.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("field %d not found", f.ID), nil)
+	}
+
+	return nil
+}
+
+// DeleteField removes the field and its values from the project's tasks
+// in one transaction.
+func (r *ProjectRepository) DeleteField(ctx context.Context, f *entities.ProjectField) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM project_fields WHERE id = $1 AND project_id = $2"
+	result, err := tx.ExecContext(ctx, query, f.ID, f.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("field %d not found", f.ID), nil)
+	}
+
+	query = `UPDATE tasks SET custom_fields = custom_fields - $1::text, version = version + 1
+		WHERE project_id = $2 AND custom_fields ? $1::text`
+	if _, err := tx.ExecContext(ctx, query, f.Name, f.ProjectID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CountOptionUse returns how many of the project's tasks, deleted ones
+// included, hold the option as the value of the field.
+func (r *ProjectRepository) CountOptionUse(ctx context.Context, projectID int, name, option string) (int, error) {
+	query := `SELECT COUNT(*) FROM tasks
+		WHERE project_id = $1 AND custom_fields -> $2::text @> to_jsonb($3::text)`
+	var n int
+	err := r.db.GetContext(ctx, &n, query, projectID, name, option)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func fieldConflict(err error, name string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("a field named %q already exists", name), err)
+	}
+
+	return err
+}
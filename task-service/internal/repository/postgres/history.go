@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// taskSnapshot is the JSON form of task t handed to consumers outside the
+// service along with its events.
+const taskSnapshot = `jsonb_build_object(
+		'id', t.id,
+		'user_id', t.user_id,
+		'parent_id', t.parent_id,
+		'assignee_id', t.assignee_id,
+		'project_id', t.project_id,
+		'title', t.title,
+		'status_id', t.status_id,
+		'priority', t.priority,
+		'deadline', t.deadline,
+		'labels', t.labels,
+		'custom_fields', t.custom_fields,
+		'version', t.version)`
+
+type eventRow struct {
+	entities.TaskEvent
+	RawChanges []byte `db:"changes"`
+}
+
+// insertEvent records ev within tx so the history never disagrees with the
+// task itself.
+func insertEvent(ctx context.Context, tx queryer, ev *entities.TaskEvent) error {
+	changes, err := json.Marshal(ev.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO task_events (task_id, actor_id, type, changes) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	if err := tx.QueryRowxContext(ctx, query, ev.TaskID, ev.ActorID, ev.Type, changes).Scan(&ev.ID, &ev.CreatedAt); err != nil {
+		return err
+	}
+
+	return eventsRecorded(ctx, tx, []int64{int64(ev.ID)})
+}
+
+// eventsRecorded runs, within the transaction that recorded them, whatever
+// has to follow new task events. Events inserted in bulk by SQL must be
+// passed here as well.
+func eventsRecorded(ctx context.Context, tx queryer, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := enqueueDeliveries(ctx, tx, ids); err != nil {
+		return err
+	}
+
+	if err := writeOutbox(ctx, tx, ids); err != nil {
+		return err
+	}
+
+	return notifyEvents(ctx, tx, ids)
+}
+
+// GetHistory returns a page of the task's events, newest first, along with
+// the total number of events.
+func (r *TaskRepository) GetHistory(ctx context.Context, taskID, limit, offset int) ([]*entities.TaskEvent, int, error) {
+	var total int
+	if err := conn(ctx, r.db).GetContext(ctx, &total, "SELECT COUNT(*) FROM task_events WHERE task_id = $1", taskID); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, task_id, actor_id, type, changes, created_at FROM task_events
+		WHERE task_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3`
+	var rows []eventRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, taskID, limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]*entities.TaskEvent, len(rows))
+	for i := range rows {
+		ev := rows[i].TaskEvent
+		if err := json.Unmarshal(rows[i].RawChanges, &ev.Changes); err != nil {
+			return nil, 0, err
+		}
+		events[i] = &ev
+	}
+
+	return events, total, nil
+}
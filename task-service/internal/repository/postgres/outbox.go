@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// outboxLockKey is the advisory lock held by the replica relaying the
+// outbox.
+const outboxLockKey = 7_340_101
+
+type OutboxRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewOutboxRepository(db *sqlx.DB, l logger.ILogger) *OutboxRepository {
+	return &OutboxRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+// writeOutbox adds the events to the outbox within the transaction that
+// recorded them, so that an event is published if and only if the change
+// it describes was committed.
+func writeOutbox(ctx context.Context, tx queryer, ids []int64) error {
+	query := `INSERT INTO outbox (event_id, type, schema_version, aggregate_id, payload)
+		SELECT u.id, e.type, $2, e.task_id, jsonb_build_object(
+			'id', u.id,
+			'type', e.type,
+			'schema_version', $2::int,
+			'source', $3::text,
+			'occurred_at', e.created_at,
+			'task_id', e.task_id,
+			'actor_id', e.actor_id,
+			'data', jsonb_build_object('changes', e.changes, 'task', ` + taskSnapshot + `))
+		FROM task_events e
+		JOIN tasks t ON t.id = e.task_id
+		CROSS JOIN LATERAL (SELECT gen_random_uuid() AS id) u
+		WHERE e.id = ANY($1)
+		ORDER BY e.id`
+	_, err := tx.ExecContext(ctx, query, pq.Array(ids), entities.EventSchemaVersion, entities.EventSource)
+	return err
+}
+
+// ProcessOutbox hands up to limit unpublished messages to publish in id
+// order and marks the published ones. It stops at the first failure, which
+// is retried at retryAt, rather than skipping ahead. Ids are taken when a
+// message is written, not when its transaction commits, so a transaction
+// committing late can still have its messages published after ones with
+// higher ids: consumers must not rely on the order and use the version in
+// the task snapshot to drop stale updates. Only one replica relays at a
+// time; the others return right away. A message may also be published
+// twice, when the commit fails after publishing; consumers rely on its
+// event id to tell.
+func (r *OutboxRepository) ProcessOutbox(ctx context.Context, limit int,
+	publish func(ctx context.Context, m *entities.OutboxMessage) error, retryAt func(attempts int) time.Time) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var relaying bool
+	if err := tx.GetContext(ctx, &relaying, "SELECT pg_try_advisory_xact_lock($1)", outboxLockKey); err != nil {
+		return 0, err
+	}
+	if !relaying {
+		return 0, nil
+	}
+
+	query := `SELECT * FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`
+	var messages []*entities.OutboxMessage
+	if err := tx.SelectContext(ctx, &messages, query, limit); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, m := range messages {
+		if m.NextAttemptAt.After(time.Now()) {
+			break
+		}
+
+		if err := publish(ctx, m); err != nil {
+			message := err.Error()
+			query := "UPDATE outbox SET attempts = attempts + 1, next_attempt_at = $1, last_error = $2 WHERE id = $3"
+			if _, err := tx.ExecContext(ctx, query, retryAt(m.Attempts+1), message, m.ID); err != nil {
+				return 0, err
+			}
+			r.logger.Warn("Failed to publish event", "event_id", m.EventID, "attempts", m.Attempts+1, "error", message)
+			break
+		}
+
+		query := "UPDATE outbox SET attempts = attempts + 1, published_at = NOW(), last_error = NULL WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, m.ID); err != nil {
+			return 0, err
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// PurgePublished removes messages published before the given time.
+func (r *OutboxRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
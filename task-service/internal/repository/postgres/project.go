@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type ProjectRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewProjectRepository(db *sqlx.DB, l logger.ILogger) *ProjectRepository {
+	return &ProjectRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+func (r *ProjectRepository) GetById(ctx context.Context, id int) (*entities.Project, error) {
+	query := "SELECT * FROM projects WHERE id = $1"
+	project := entities.Project{}
+	err := r.db.GetContext(ctx, &project, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("project %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// GetAllByMemberId returns the projects the user is a member of.
+func (r *ProjectRepository) GetAllByMemberId(ctx context.Context, userID int) ([]*entities.Project, error) {
+	query := `SELECT p.* FROM projects p
+		JOIN project_members m ON m.project_id = p.id
+		WHERE m.user_id = $1 ORDER BY p.id`
+	var projects []*entities.Project
+	err := r.db.SelectContext(ctx, &projects, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Create inserts the project and registers its owner as a member in one
+// transaction.
+func (r *ProjectRepository) Create(ctx context.Context, p *entities.Project) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO projects (name, description, owner_id) VALUES ($1, $2, $3) RETURNING id, archived, created_at"
+	if err := tx.QueryRowxContext(ctx, query, p.Name, p.Description, p.OwnerID).Scan(&p.ID, &p.Archived, &p.CreatedAt); err != nil {
+		r.logger.Error("Error while inserting new project in repository", "name", p.Name, "owner_id", p.OwnerID)
+		return err
+	}
+
+	query = "INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3)"
+	if _, err := tx.ExecContext(ctx, query, p.ID, p.OwnerID, entities.RoleOwner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, p *entities.Project) error {
+	query := "UPDATE projects SET name = $1, description = $2, archived = $3 WHERE id = $4"
+	result, err := r.db.ExecContext(ctx, query, p.Name, p.Description, p.Archived, p.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("project %d not found", p.ID), nil)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM projects WHERE id = $1"
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("project %d not found", id), nil)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) GetMembers(ctx context.Context, projectID int) ([]*entities.ProjectMember, error) {
+	query := "SELECT * FROM project_members WHERE project_id = $1 ORDER BY user_id"
+	var members []*entities.ProjectMember
+	err := r.db.SelectContext(ctx, &members, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// GetMemberRole returns the user's role in the project or app.ErrNotFound
+// when the user is not a member.
+func (r *ProjectRepository) GetMemberRole(ctx context.Context, projectID, userID int) (string, error) {
+	query := "SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2"
+	var role string
+	err := r.db.GetContext(ctx, &role, query, projectID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", app.NewAppError(app.ErrNotFound, fmt.Sprintf("user %d is not a member of project %d", userID, projectID), err)
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// SetMember adds the user to the project or changes their role.
+func (r *ProjectRepository) SetMember(ctx context.Context, m *entities.ProjectMember) error {
+	query := `INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+	_, err := r.db.ExecContext(ctx, query, m.ProjectID, m.UserID, m.Role)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID, userID int) error {
+	query := "DELETE FROM project_members WHERE project_id = $1 AND user_id = $2"
+	result, err := r.db.ExecContext(ctx, query, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("user %d is not a member of project %d", userID, projectID), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReminderRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewReminderRepository(db *sqlx.DB, l logger.ILogger) *ReminderRepository {
+	return &ReminderRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+// GetSettings returns the user's reminder offsets, nil when the user has
+// not configured any.
+func (r *ReminderRepository) GetSettings(ctx context.Context, userID int) ([]int, error) {
+	query := "SELECT offsets_minutes FROM reminder_settings WHERE user_id = $1"
+	var offsets pq.Int64Array
+	err := r.db.GetContext(ctx, &offsets, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := make([]int, len(offsets))
+	for i, o := range offsets {
+		result[i] = int(o)
+	}
+
+	return result, nil
+}
+
+func (r *ReminderRepository) SaveSettings(ctx context.Context, s *entities.ReminderSettings) error {
+	query := `INSERT INTO reminder_settings (user_id, offsets_minutes) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET offsets_minutes = EXCLUDED.offsets_minutes`
+	_, err := r.db.ExecContext(ctx, query, s.UserID, pq.Array(s.OffsetsMinutes))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reminderLease is how long a claimed reminder is reserved for the
+// replica sending it. A replica that dies mid-batch leaves its claims to
+// expire, after which the reminders are due again.
+const reminderLease = 15 * time.Minute
+
+// ProcessDue sends every due and not yet sent reminder through each of
+// the channels. The reminders are first claimed in a short transaction,
+// with the due tasks locked SKIP LOCKED so that several replicas running
+// the scheduler never claim the same reminder; the claims are committed
+// before anything is sent, so no locks are held while waiting on mail
+// servers or webhooks. Every delivery is recorded per channel: a reminder
+// counts as sent once all channels delivered it, otherwise its claim is
+// released and the next run retries only the channels that failed.
+// Reminders more than lookback past their due time are skipped.
+func (r *ReminderRepository) ProcessDue(ctx context.Context, defaultOffsets []int, lookback time.Duration, limit int, channels []string,
+	send func(ctx context.Context, rem *entities.Reminder, channel string) error) (int, error) {
+	due, err := r.claimDue(ctx, defaultOffsets, lookback, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, rem := range due {
+		rem.Offset = time.Duration(rem.OffsetMinutes) * time.Minute
+
+		var delivered []string
+		query := "SELECT channel FROM sent_reminder_channels WHERE task_id = $1 AND offset_minutes = $2"
+		if err := r.db.SelectContext(ctx, &delivered, query, rem.TaskID, rem.OffsetMinutes); err != nil {
+			return sent, err
+		}
+
+		failed := false
+		for _, channel := range channels {
+			if slices.Contains(delivered, channel) {
+				continue
+			}
+
+			if err := send(ctx, rem, channel); err != nil {
+				r.logger.Error("Failed to send reminder", "task_id", rem.TaskID, "offset_minutes", rem.OffsetMinutes,
+					"channel", channel, "error", err.Error())
+				failed = true
+				continue
+			}
+
+			query := `INSERT INTO sent_reminder_channels (task_id, offset_minutes, channel) VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING`
+			if _, err := r.db.ExecContext(ctx, query, rem.TaskID, rem.OffsetMinutes, channel); err != nil {
+				return sent, err
+			}
+		}
+
+		if failed {
+			if err := r.release(ctx, rem); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		query = `UPDATE sent_reminders SET sent_at = NOW(), claimed_until = NULL
+			WHERE task_id = $1 AND offset_minutes = $2`
+		if _, err := r.db.ExecContext(ctx, query, rem.TaskID, rem.OffsetMinutes); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// claimDue selects up to limit due reminders and leases them to the
+// caller for reminderLease.
+func (r *ReminderRepository) claimDue(ctx context.Context, defaultOffsets []int, lookback time.Duration, limit int) ([]*entities.Reminder, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT t.id AS task_id, t.title, t.deadline, COALESCE(t.assignee_id, t.user_id) AS user_id, o.offset_minutes
+		FROM tasks t
+		CROSS JOIN LATERAL unnest(COALESCE(
+			(SELECT s.offsets_minutes FROM reminder_settings s WHERE s.user_id = COALESCE(t.assignee_id, t.user_id)),
+			$1::int[])) AS o(offset_minutes)
+		WHERE t.status_id <> $2 AND t.deleted_at IS NULL
+		  AND t.deadline - o.offset_minutes * INTERVAL '1 minute' <= NOW()
+		  AND t.deadline - o.offset_minutes * INTERVAL '1 minute' > NOW() - $3 * INTERVAL '1 second'
+		  AND NOT EXISTS (SELECT 1 FROM sent_reminders sr WHERE sr.task_id = t.id AND sr.offset_minutes = o.offset_minutes
+		                  AND (sr.sent_at IS NOT NULL OR sr.claimed_until > NOW()))
+		ORDER BY t.deadline
+		LIMIT $4
+		FOR UPDATE OF t SKIP LOCKED`
+	var due []*entities.Reminder
+	err = tx.SelectContext(ctx, &due, query, pq.Array(defaultOffsets), entities.StatusCompleted, int64(lookback.Seconds()), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `INSERT INTO sent_reminders (task_id, offset_minutes, sent_at, claimed_until) VALUES ($1, $2, NULL, NOW() + $3 * INTERVAL '1 second')
+		ON CONFLICT (task_id, offset_minutes) DO UPDATE SET claimed_until = EXCLUDED.claimed_until`
+	for _, rem := range due {
+		if _, err := tx.ExecContext(ctx, query, rem.TaskID, rem.OffsetMinutes, int64(reminderLease.Seconds())); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return due, nil
+}
+
+// release gives up the claim on a reminder that could not be sent through
+// every channel so the next run retries the remaining ones.
+func (r *ReminderRepository) release(ctx context.Context, rem *entities.Reminder) error {
+	query := "DELETE FROM sent_reminders WHERE task_id = $1 AND offset_minutes = $2 AND sent_at IS NULL"
+	_, err := r.db.ExecContext(ctx, query, rem.TaskID, rem.OffsetMinutes)
+	return err
+}
+
+// ResetReminders forgets the reminders sent for a task, e.g. after its
+// deadline has moved.
+func (r *TaskRepository) ResetReminders(ctx context.Context, id int) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM sent_reminders WHERE task_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, "DELETE FROM sent_reminder_channels WHERE task_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
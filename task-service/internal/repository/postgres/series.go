@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+func (r *TaskRepository) CreateSeries(ctx context.Context, s *entities.TaskSeries) error {
+	query := `INSERT INTO task_series (user_id, rrule, dtstart, start_occurrence, title, description, priority, assignee_id, project_id, parent_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id`
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, s.UserID, s.RRule, s.DTStart, s.StartOccurrence, s.Title, s.Description,
+		s.Priority, s.AssigneeID, s.ProjectID, s.ParentID).Scan(&s.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) GetSeries(ctx context.Context, id int) (*entities.TaskSeries, error) {
+	query := "SELECT * FROM task_series WHERE id = $1"
+	series := entities.TaskSeries{}
+	err := conn(ctx, r.db).GetContext(ctx, &series, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("series %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *TaskRepository) UpdateSeries(ctx context.Context, s *entities.TaskSeries) error {
+	query := `UPDATE task_series SET rrule = $1, dtstart = $2, start_occurrence = $3, title = $4, description = $5,
+		priority = $6, assignee_id = $7, project_id = $8, parent_id = $9 WHERE id = $10`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, s.RRule, s.DTStart, s.StartOccurrence, s.Title, s.Description,
+		s.Priority, s.AssigneeID, s.ProjectID, s.ParentID, s.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOccurrencesAfter copies the series template onto its open
+// occurrences later than the given one and shifts their deadlines.
+func (r *TaskRepository) UpdateOccurrencesAfter(ctx context.Context, s *entities.TaskSeries, occurrence int, shift time.Duration, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before []*entities.Task
+	query := "SELECT * FROM tasks WHERE series_id = $1 AND occurrence > $2 AND status_id <> $3 AND deleted_at IS NULL ORDER BY id FOR UPDATE"
+	if err := tx.SelectContext(ctx, &before, query, s.ID, occurrence, entities.StatusCompleted); err != nil {
+		return err
+	}
+
+	query = `UPDATE tasks SET title = $1, description = $2, priority = $3, assignee_id = $4, project_id = $5, rrule = $6,
+		deadline = deadline + $7 * INTERVAL '1 microsecond', version = version + 1
+		WHERE series_id = $8 AND occurrence > $9 AND status_id <> $10 AND deleted_at IS NULL`
+	_, err = tx.ExecContext(ctx, query, s.Title, s.Description, s.Priority, s.AssigneeID, s.ProjectID, s.RRule,
+		shift.Microseconds(), s.ID, occurrence, entities.StatusCompleted)
+	if err != nil {
+		return err
+	}
+
+	for _, old := range before {
+		updated := *old
+		updated.Title, updated.Description, updated.Priority = s.Title, s.Description, s.Priority
+		updated.AssigneeID, updated.ProjectID, updated.RRule = s.AssigneeID, s.ProjectID, &s.RRule
+		updated.Deadline = old.Deadline.Add(shift)
+		if err := recordChanges(ctx, tx, old, &updated, actorID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// OccurrenceExists reports whether the series already has the given
+// occurrence, so completing a task twice does not spawn duplicates.
+// Occurrences in the trash count as well, as restoring them would clash.
+func (r *TaskRepository) OccurrenceExists(ctx context.Context, seriesID, occurrence int) (bool, error) {
+	query := "SELECT EXISTS (SELECT 1 FROM tasks WHERE series_id = $1 AND occurrence = $2)"
+	var exists bool
+	err := conn(ctx, r.db).GetContext(ctx, &exists, query, seriesID, occurrence)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
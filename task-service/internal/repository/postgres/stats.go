@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// statsVisible limits tasks aliased t to those visible to user $1, and to
+// project $2 when it is not null. The range, where needed, is $3 to $4.
+var statsVisible = taskVisible("t", "$1") + `
+	AND ($2::int IS NULL OR t.project_id = $2)`
+
+// GetStats computes the statistics for the filter. Tasks without a
+// deadline are stored with the zero time and never overdue.
+func (r *TaskRepository) GetStats(ctx context.Context, f entities.StatsFilter) (*entities.Stats, error) {
+	args := []interface{}{f.UserID, f.ProjectID, f.From, f.To}
+	db := conn(ctx, r.db)
+
+	stats := entities.Stats{From: f.From, To: f.To}
+
+	query := `SELECT s.id AS status_id, s.code, COUNT(t.id) AS count
+		FROM task_statuses s
+		LEFT JOIN tasks t ON t.status_id = s.id AND t.deleted_at IS NULL AND ` + statsVisible + `
+		GROUP BY s.id, s.code ORDER BY s.id`
+	if err := db.SelectContext(ctx, &stats.ByStatus, query, args[:2]...); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`SELECT
+			COUNT(*) FILTER (WHERE t.status_id <> %[1]d AND t.deadline > '0001-01-01T00:00:00Z' AND t.deadline < NOW()) AS overdue,
+			AVG(EXTRACT(EPOCH FROM t.completed_at - t.created_at))
+				FILTER (WHERE t.status_id = %[1]d AND t.completed_at >= $3 AND t.completed_at < $4) AS average_lead_time
+		FROM tasks t
+		WHERE t.deleted_at IS NULL AND `+statsVisible, entities.StatusCompleted)
+	if err := db.QueryRowxContext(ctx, query, args...).Scan(&stats.Overdue, &stats.AverageLeadTime); err != nil {
+		return nil, err
+	}
+
+	// A task counts as remaining at the end of a day when it existed, was
+	// not in the trash and was not completed by then.
+	query = fmt.Sprintf(`SELECT to_char(d.day AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date,
+			COUNT(t.id) AS remaining,
+			COALESCE(SUM(t.estimate_points), 0) AS remaining_points,
+			COALESCE(SUM(t.estimate_hours), 0) AS remaining_hours,
+			(SELECT COUNT(*) FROM tasks t
+				WHERE t.status_id = %[1]d AND t.completed_at >= d.day AND t.completed_at < d.day + INTERVAL '1 day'
+				AND t.deleted_at IS NULL AND `+statsVisible+`) AS completed
+		FROM generate_series($3::timestamptz, $4::timestamptz - INTERVAL '1 day', INTERVAL '1 day') AS d(day)
+		LEFT JOIN tasks t ON t.created_at < d.day + INTERVAL '1 day'
+			AND (t.deleted_at IS NULL OR t.deleted_at >= d.day + INTERVAL '1 day')
+			AND (t.status_id <> %[1]d OR t.completed_at >= d.day + INTERVAL '1 day')
+			AND `+statsVisible+`
+		GROUP BY d.day ORDER BY d.day`, entities.StatusCompleted)
+	if err := db.SelectContext(ctx, &stats.Burndown, query, args...); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
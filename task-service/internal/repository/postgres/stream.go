@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/lib/pq"
+)
+
+const (
+	// eventsChannel is the NOTIFY channel carrying the ids of committed
+	// task events, comma separated.
+	eventsChannel = "task_events"
+	// maxNotifyIDs keeps notification payloads well below the 8000 byte
+	// limit of NOTIFY.
+	maxNotifyIDs = 500
+)
+
+// notifyEvents announces the events to every listening replica. NOTIFY is
+// transactional: nothing is sent unless the events are committed.
+func notifyEvents(ctx context.Context, tx queryer, ids []int64) error {
+	for start := 0; start < len(ids); start += maxNotifyIDs {
+		chunk := ids[start:min(start+maxNotifyIDs, len(ids))]
+
+		parts := make([]string, len(chunk))
+		for i, id := range chunk {
+			parts[i] = strconv.FormatInt(id, 10)
+		}
+
+		if _, err := tx.ExecContext(ctx, "SELECT pg_notify($1, $2)", eventsChannel, strings.Join(parts, ",")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LastStreamEventID returns the id of the last task event, 0 when there is
+// none.
+func (r *TaskRepository) LastStreamEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := conn(ctx, r.db).GetContext(ctx, &id, "SELECT COALESCE(MAX(id), 0) FROM task_events")
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetStreamEvents loads the given events, in id order, with their tasks.
+// Events of purged tasks are left out.
+func (r *TaskRepository) GetStreamEvents(ctx context.Context, ids []int64) ([]*entities.StreamEvent, error) {
+	query := `SELECT e.id, e.task_id, e.actor_id, e.type, e.changes, e.created_at FROM task_events e
+		WHERE e.id = ANY($1) ORDER BY e.id`
+	return r.streamEvents(ctx, query, pq.Array(ids))
+}
+
+// GetStreamEventsAfter returns up to limit events following afterID on the
+// tasks userID can currently see, in id order. User 0 sees every task.
+func (r *TaskRepository) GetStreamEventsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]*entities.StreamEvent, error) {
+	query := `SELECT e.id, e.task_id, e.actor_id, e.type, e.changes, e.created_at FROM task_events e
+		JOIN tasks t ON t.id = e.task_id
+		WHERE e.id > $1 AND ($2 = 0 OR ` + taskVisible("t", "$2") + `)
+		ORDER BY e.id LIMIT $3`
+	return r.streamEvents(ctx, query, afterID, userID, limit)
+}
+
+func (r *TaskRepository) streamEvents(ctx context.Context, query string, args ...interface{}) ([]*entities.StreamEvent, error) {
+	var rows []eventRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	taskIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		taskIDs = append(taskIDs, int64(row.TaskID))
+	}
+
+	// Deleted tasks are included on purpose: their deletion is an event
+	// like any other.
+	var tasks []*entities.Task
+	if err := conn(ctx, r.db).SelectContext(ctx, &tasks, "SELECT * FROM tasks WHERE id = ANY($1)", pq.Array(taskIDs)); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*entities.Task, len(tasks))
+	var projectIDs []int64
+	for _, t := range tasks {
+		byID[t.ID] = t
+		if t.ProjectID != nil {
+			projectIDs = append(projectIDs, int64(*t.ProjectID))
+		}
+	}
+
+	var members []entities.ProjectMember
+	if len(projectIDs) > 0 {
+		query := "SELECT * FROM project_members WHERE project_id = ANY($1)"
+		if err := conn(ctx, r.db).SelectContext(ctx, &members, query, pq.Array(projectIDs)); err != nil {
+			return nil, err
+		}
+	}
+
+	events := make([]*entities.StreamEvent, 0, len(rows))
+	for i := range rows {
+		t, ok := byID[rows[i].TaskID]
+		if !ok {
+			continue
+		}
+
+		ev := &entities.StreamEvent{TaskEvent: rows[i].TaskEvent, Task: t}
+		if err := json.Unmarshal(rows[i].RawChanges, &ev.Changes); err != nil {
+			return nil, err
+		}
+
+		// The same rule as taskVisible.
+		if t.ProjectID == nil {
+			ev.Viewers = append(ev.Viewers, t.UserId)
+			if t.AssigneeID != nil {
+				ev.Viewers = append(ev.Viewers, *t.AssigneeID)
+			}
+		}
+		for _, m := range members {
+			if t.ProjectID != nil && m.ProjectID == *t.ProjectID {
+				ev.Viewers = append(ev.Viewers, m.UserID)
+			}
+		}
+
+		events = append(events, ev)
+	}
+
+	return events, nil
+}
+
+// EventListener receives the ids of task events committed by any replica.
+type EventListener struct {
+	dsn    string
+	logger logger.ILogger
+}
+
+func NewEventListener(dsn string, l logger.ILogger) *EventListener {
+	return &EventListener{
+		dsn:    dsn,
+		logger: l,
+	}
+}
+
+// Listen calls notify with the ids of every batch of committed events
+// until ctx is done. Notifications sent while the connection was down are
+// lost; reconnected is called once it is back so that the caller can
+// catch up from the database.
+func (l *EventListener) Listen(ctx context.Context, notify func(ids []int64), reconnected func()) error {
+	listener := pq.NewListener(l.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventDisconnected:
+			l.logger.Warn("Task event listener disconnected", "error", errString(err))
+		case pq.ListenerEventReconnected:
+			l.logger.Info("Task event listener reconnected")
+		case pq.ListenerEventConnectionAttemptFailed:
+			l.logger.Error("Task event listener cannot connect", "error", errString(err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(eventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established.
+			if n == nil {
+				reconnected()
+				continue
+			}
+
+			var ids []int64
+			for _, part := range strings.Split(n.Extra, ",") {
+				id, err := strconv.ParseInt(part, 10, 64)
+				if err != nil {
+					l.logger.Warn("Ignoring malformed task event notification", "payload", n.Extra)
+					continue
+				}
+				ids = append(ids, id)
+			}
+			notify(ids)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
 	"github.com/dielit66/task-management-system/internal/logger"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type TaskRepository struct {
@@ -23,10 +27,13 @@ func NewTaskRepository(db *sqlx.DB, l logger.ILogger) *TaskRepository {
 }
 
 func (r *TaskRepository) GetById(ctx context.Context, id int) (*entities.Task, error) {
-	query := "SELECT * FROM tasks  WHERE id = $1"
+	query := "SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NULL"
 	task := entities.Task{}
-	err := r.db.Get(&task, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &task, query, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("task %d not found", id), err)
+		}
 		return nil, err
 	}
 
@@ -34,71 +41,474 @@ func (r *TaskRepository) GetById(ctx context.Context, id int) (*entities.Task, e
 
 }
 
-func (r *TaskRepository) GetAllByUserId(ctx context.Context, id int) ([]*entities.Task, error) {
-	query := "SELECT * FROM tasks WHERE user_id = $1"
+// List returns the tasks visible to f.UserID matching the filter, as
+// taskVisible defines it.
+func (r *TaskRepository) List(ctx context.Context, f entities.TaskFilter) ([]*entities.Task, error) {
+	query, args := listQuery(f)
 	var tasks []*entities.Task
-	err := r.db.SelectContext(ctx, &tasks, query, id)
+	err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	return tasks, nil
+}
+
+// Each calls fn for every task List would return, reading them one at a
+// time so that large result sets can be streamed.
+func (r *TaskRepository) Each(ctx context.Context, f entities.TaskFilter, fn func(t *entities.Task) error) error {
+	query, args := listQuery(f)
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task entities.Task
+		if err := rows.StructScan(&task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
 
+// taskVisible is the SQL condition for the task aliased alias, none when
+// empty, being visible to the user given by the expression user. It is the
+// rule requireTaskAccess applies in the usecases: project tasks are visible
+// to the members of the project, other tasks to their creator and assignee.
+func taskVisible(alias, user string) string {
+	if alias != "" {
+		alias += "."
+	}
+
+	return fmt.Sprintf(`(%[1]sproject_id IN (SELECT project_id FROM project_members WHERE user_id = %[2]s)
+		OR %[1]sproject_id IS NULL AND (%[1]suser_id = %[2]s OR %[1]sassignee_id = %[2]s))`, alias, user)
 }
 
-func (r *TaskRepository) Create(ctx context.Context, t *entities.Task) error {
-	query := "INSERT INTO tasks (title, description, deadline, user_id, status_id) VALUES ($1,$2,$3,$4,$5)"
-	fmt.Println(t.UserId)
-	row := r.db.QueryRowContext(ctx, query, t.Title, t.Description, t.Deadline, t.UserId, 1)
+func listQuery(f entities.TaskFilter) (string, []interface{}) {
+	conditions := []string{taskVisible("", "$1")}
+	args := []interface{}{f.UserID}
+
+	if f.Deleted {
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if f.AssigneeID != nil {
+		args = append(args, *f.AssigneeID)
+		conditions = append(conditions, fmt.Sprintf("assignee_id = $%d", len(args)))
+	}
+
+	if f.ProjectID != nil {
+		args = append(args, *f.ProjectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	if len(f.FieldMatch) > 0 {
+		args = append(args, f.FieldMatch)
+		conditions = append(conditions, fmt.Sprintf("custom_fields @> $%d", len(args)))
+	}
+
+	order := " ORDER BY rank, id"
+	if f.Deleted {
+		order = " ORDER BY deleted_at DESC, id"
+	}
+
+	return "SELECT * FROM tasks WHERE " + strings.Join(conditions, " AND ") + order, args
+}
+
+// Create inserts the task at the bottom of its board column and records
+// its creation by actorID.
+func (r *TaskRepository) Create(ctx context.Context, t *entities.Task, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	column, err := lockColumn(ctx, tx, t.ProjectID, t.UserId, entities.StatusNew)
+	if err != nil {
+		return err
+	}
+
+	t.Rank, err = rankAt(ctx, tx, column, len(column))
+	if err != nil {
+		return err
+	}
 
-	if row.Err() != nil {
+	query := `INSERT INTO tasks (title, description, deadline, user_id, status_id, parent_id, assignee_id, project_id, rank, priority,
+		rrule, series_id, occurrence, labels, estimate_points, estimate_hours, custom_fields)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,COALESCE($14::text[], '{}'),$15,$16,$17) RETURNING id, created_at, status_id, version`
+	row := tx.QueryRowxContext(ctx, query, t.Title, t.Description, t.Deadline, t.UserId, entities.StatusNew, t.ParentID, t.AssigneeID, t.ProjectID, t.Rank, t.Priority,
+		t.RRule, t.SeriesID, t.Occurrence, pq.Array(t.Labels), t.EstimatePoints, t.EstimateHours, t.CustomFields)
+
+	if err := row.Scan(&t.ID, &t.CreatedAt, &t.StatusID, &t.Version); err != nil {
 		r.logger.Error("Error while inserting new task in repository", "title", t.Title, "desc", t.Description, "deadline", t.Deadline, "user_id", t.UserId)
-		fmt.Println("qqqqqqqqq")
-		return row.Err()
+		return err
 	}
 
-	return nil
+	event := entities.TaskEvent{TaskID: t.ID, ActorID: actorID, Type: entities.EventTaskCreated, Changes: entities.DiffTasks(nil, t)}
+	if err := insertEvent(ctx, tx, &event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *TaskRepository) Update(ctx context.Context, t *entities.Task) error {
-	query := "UPDATE tasks SET title = $1, description = $2, deadline = $3 WHERE id = $4"
-	result, err := r.db.ExecContext(ctx, query, t.Title, t.Description, t.Deadline, t.ID)
+// Update replaces the task and records the fields actorID changed. A
+// non-zero t.Version must match the stored one, otherwise the task was
+// modified concurrently and nothing is written. A task changing its board
+// column goes to the bottom of the new one.
+func (r *TaskRepository) Update(ctx context.Context, t *entities.Task, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
+	current, moving, err := getForColumn(ctx, tx, t.ID, func(current *entities.Task) boardColumn {
+		return columnOf(t.ProjectID, current.UserId, t.StatusID)
+	})
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if err := checkVersion(current, t.Version); err != nil {
+		return err
+	}
+
+	updated := *t
+	updated.UserId, updated.CreatedAt, updated.Rank = current.UserId, current.CreatedAt, current.Rank
+	if moving {
+		if updated.Rank, err = bottomRank(ctx, tx, &updated); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE tasks SET title = $1, description = $2, deadline = $3, parent_id = $4, status_id = $5, assignee_id = $6, project_id = $7,
+		priority = $8, rrule = $9, series_id = $10, occurrence = $11, estimate_points = $12, estimate_hours = $13, custom_fields = $14,
+		rank = $15, ` + setCompletedAt("$5") + `, version = version + 1 WHERE id = $16 RETURNING completed_at`
+	err = tx.GetContext(ctx, &t.CompletedAt, query, t.Title, t.Description, t.Deadline, t.ParentID, t.StatusID, t.AssigneeID, t.ProjectID,
+		t.Priority, t.RRule, t.SeriesID, t.Occurrence, t.EstimatePoints, t.EstimateHours, t.CustomFields, updated.Rank, t.ID)
 
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("no task found with id %d", t.ID)
+	if err := recordChanges(ctx, tx, current, &updated, actorID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
 	}
 
-	log.Printf("Updated %d rows", rowsAffected)
+	t.CreatedAt, t.Rank = current.CreatedAt, updated.Rank
+	t.Version = current.Version + 1
 
 	return nil
 }
 
-func (r *TaskRepository) Delete(ctx context.Context, id int) error {
-	query := "DELETE from tasks WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, id)
+// UpdateColumns writes only the given columns of t and records the fields
+// actorID changed. Like Update it honours a non-zero t.Version and ranks a
+// task changing its board column at the bottom of the new one.
+func (r *TaskRepository) UpdateColumns(ctx context.Context, t *entities.Task, columns []string, actorID int) error {
+	sets := make([]string, 0, len(columns)+2)
+	args := make([]interface{}, 0, len(columns)+2)
+	for _, column := range columns {
+		value, ok := columnValue(t, column)
+		if !ok {
+			return fmt.Errorf("column %q of tasks cannot be updated", column)
+		}
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+		if column == "status_id" {
+			sets = append(sets, setCompletedAt(fmt.Sprintf("$%d", len(args))))
+		}
+	}
+	sets = append(sets, "version = version + 1")
 
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	// Columns that are not written keep their stored values, which may
+	// have changed since t was read.
+	withColumns := func(current *entities.Task) entities.Task {
+		updated := *current
+		for _, column := range columns {
+			copyColumn(&updated, t, column)
+		}
+		return updated
+	}
+
+	current, moving, err := getForColumn(ctx, tx, t.ID, func(current *entities.Task) boardColumn {
+		updated := withColumns(current)
+		return columnOf(updated.ProjectID, updated.UserId, updated.StatusID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(current, t.Version); err != nil {
+		return err
+	}
+
+	updated := withColumns(current)
+	if moving {
+		if updated.Rank, err = bottomRank(ctx, tx, &updated); err != nil {
+			return err
+		}
+		args = append(args, updated.Rank)
+		sets = append(sets, fmt.Sprintf("rank = $%d", len(args)))
+	}
+	args = append(args, t.ID)
+
+	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = $%d RETURNING completed_at", strings.Join(sets, ", "), len(args))
+	if err := tx.GetContext(ctx, &t.CompletedAt, query, args...); err != nil {
+		return err
+	}
+
+	if err := recordChanges(ctx, tx, current, &updated, actorID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	t.Rank = updated.Rank
+	t.Version = current.Version + 1
+
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// Delete moves the task and its subtasks to the trash and records the
+// deletion of each of them. A non-zero version must match the stored one.
+func (r *TaskRepository) Delete(ctx context.Context, id, version, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
+	current, err := getForUpdate(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	r.logger.Info("Deleted %d rows", rowsAffected)
+	if err := checkVersion(current, version); err != nil {
+		return err
+	}
 
-	return err
+	event := entities.TaskEvent{TaskID: id, ActorID: actorID, Type: entities.EventTaskDeleted, Changes: entities.DiffTasks(current, nil)}
+	if err := insertEvent(ctx, tx, &event); err != nil {
+		return err
+	}
+
+	// The whole subtree shares the deletion time, which is how Restore
+	// tells it apart from subtasks trashed earlier on their own.
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		), events AS (
+			INSERT INTO task_events (task_id, actor_id, type) SELECT id, $2, $3 FROM subtree RETURNING id
+		), deleted AS (
+			UPDATE tasks SET deleted_at = NOW(), version = version + 1
+			WHERE id = $1 OR id IN (SELECT id FROM subtree) RETURNING id
+		)
+		SELECT (SELECT COUNT(*) FROM deleted), (SELECT COALESCE(array_agg(id), '{}') FROM events)`
+	var rowsAffected int
+	var ids pq.Int64Array
+	if err := tx.QueryRowxContext(ctx, query, id, actorID, entities.EventTaskDeleted).Scan(&rowsAffected, &ids); err != nil {
+		return err
+	}
+
+	if err := eventsRecorded(ctx, tx, ids); err != nil {
+		return err
+	}
+
+	r.logger.Info("Moved task to the trash", "task_id", id, "rows", rowsAffected)
+
+	return tx.Commit()
+}
+
+// getForUpdate loads the task and locks it for the rest of tx.
+func getForUpdate(ctx context.Context, tx queryer, id int) (*entities.Task, error) {
+	task := entities.Task{}
+	err := tx.GetContext(ctx, &task, "SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("no task found with id %d", id), err)
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// checkVersion fails when the caller expected another version of the task
+// than the stored one. Version 0 skips the check.
+func checkVersion(current *entities.Task, version int) error {
+	if version != 0 && version != current.Version {
+		return app.NewAppError(app.ErrPrecondition, fmt.Sprintf("task %d has been modified, current version is %d", current.ID, current.Version), nil)
+	}
 
+	return nil
+}
+
+// recordChanges writes an event for the fields that differ between old and
+// new. Writes that change nothing tracked leave no event behind.
+func recordChanges(ctx context.Context, tx queryer, old, new *entities.Task, actorID int) error {
+	changes := entities.DiffTasks(old, new)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	event := entities.TaskEvent{TaskID: old.ID, ActorID: actorID, Type: entities.EventTaskUpdated, Changes: changes}
+	if _, ok := changes["status_id"]; ok {
+		event.Type = entities.EventTaskStatusChanged
+	}
+
+	return insertEvent(ctx, tx, &event)
+}
+
+// GetDescendants returns every task below the given one, ordered so that
+// parents always come before their children.
+func (r *TaskRepository) GetDescendants(ctx context.Context, id int) ([]*entities.Task, error) {
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id, 1 AS depth FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		SELECT t.* FROM tasks t JOIN subtree s ON s.id = t.id ORDER BY s.depth, t.id`
+	var tasks []*entities.Task
+	err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// IsAncestor reports whether ancestorID is id itself or one of its parents.
+func (r *TaskRepository) IsAncestor(ctx context.Context, ancestorID, id int) (bool, error) {
+	query := `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM tasks WHERE id = $1
+			UNION
+			SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`
+	var found bool
+	err := conn(ctx, r.db).GetContext(ctx, &found, query, id, ancestorID)
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// CountOpenChildren returns how many direct subtasks are not completed yet.
+func (r *TaskRepository) CountOpenChildren(ctx context.Context, id int) (int, error) {
+	query := "SELECT COUNT(*) FROM tasks WHERE parent_id = $1 AND status_id <> $2 AND deleted_at IS NULL"
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, id, entities.StatusCompleted)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// setCompletedAt keeps completed_at in step with the status written from
+// the given parameter: it is set when the task becomes completed and
+// cleared when it is reopened.
+func setCompletedAt(status string) string {
+	return fmt.Sprintf("completed_at = CASE WHEN %s = %d THEN COALESCE(completed_at, NOW()) END", status, entities.StatusCompleted)
+}
+
+// columnValue returns the value of a writable column of t.
+func columnValue(t *entities.Task, column string) (interface{}, bool) {
+	switch column {
+	case "title":
+		return t.Title, true
+	case "description":
+		return t.Description, true
+	case "deadline":
+		return t.Deadline, true
+	case "parent_id":
+		return t.ParentID, true
+	case "status_id":
+		return t.StatusID, true
+	case "assignee_id":
+		return t.AssigneeID, true
+	case "project_id":
+		return t.ProjectID, true
+	case "priority":
+		return t.Priority, true
+	case "rrule":
+		return t.RRule, true
+	case "series_id":
+		return t.SeriesID, true
+	case "occurrence":
+		return t.Occurrence, true
+	case "labels":
+		if t.Labels == nil {
+			return pq.Array([]string{}), true
+		}
+		return pq.Array(t.Labels), true
+	case "estimate_points":
+		return t.EstimatePoints, true
+	case "estimate_hours":
+		return t.EstimateHours, true
+	case "custom_fields":
+		return t.CustomFields, true
+	}
+
+	return nil, false
+}
+
+// copyColumn copies the field backing column from src to dst.
+func copyColumn(dst, src *entities.Task, column string) {
+	switch column {
+	case "title":
+		dst.Title = src.Title
+	case "description":
+		dst.Description = src.Description
+	case "deadline":
+		dst.Deadline = src.Deadline
+	case "parent_id":
+		dst.ParentID = src.ParentID
+	case "status_id":
+		dst.StatusID = src.StatusID
+	case "assignee_id":
+		dst.AssigneeID = src.AssigneeID
+	case "project_id":
+		dst.ProjectID = src.ProjectID
+	case "priority":
+		dst.Priority = src.Priority
+	case "rrule":
+		dst.RRule = src.RRule
+	case "series_id":
+		dst.SeriesID = src.SeriesID
+	case "occurrence":
+		dst.Occurrence = src.Occurrence
+	case "labels":
+		dst.Labels = src.Labels
+	case "estimate_points":
+		dst.EstimatePoints = src.EstimatePoints
+	case "estimate_hours":
+		dst.EstimateHours = src.EstimateHours
+	case "custom_fields":
+		dst.CustomFields = src.CustomFields
+	}
 }
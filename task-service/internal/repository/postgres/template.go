@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TemplateRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewTemplateRepository(db *sqlx.DB, l logger.ILogger) *TemplateRepository {
+	return &TemplateRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+type templateRow struct {
+	entities.Template
+	RawBody []byte `db:"body"`
+}
+
+func (row *templateRow) template() (*entities.Template, error) {
+	t := row.Template
+	if err := json.Unmarshal(row.RawBody, &t.TemplateTask); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, t *entities.Template) error {
+	body, err := json.Marshal(t.TemplateTask)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO templates (user_id, project_id, name, body) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+	err = r.db.QueryRowxContext(ctx, query, t.UserID, t.ProjectID, t.Name, body).
+		Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	return templateConflict(err, t.Name)
+}
+
+func (r *TemplateRepository) GetById(ctx context.Context, id int) (*entities.Template, error) {
+	var row templateRow
+	if err := r.db.GetContext(ctx, &row, "SELECT * FROM templates WHERE id = $1", id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("template %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return row.template()
+}
+
+// GetAllForUser returns the user's own templates and those shared with
+// them through their projects.
+func (r *TemplateRepository) GetAllForUser(ctx context.Context, userID int) ([]*entities.Template, error) {
+	query := `SELECT * FROM templates
+		WHERE user_id = $1 OR project_id IN (SELECT project_id FROM project_members WHERE user_id = $1)
+		ORDER BY name, id`
+	var rows []templateRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, err
+	}
+
+	templates := make([]*entities.Template, len(rows))
+	for i := range rows {
+		t, err := rows[i].template()
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = t
+	}
+
+	return templates, nil
+}
+
+func (r *TemplateRepository) Update(ctx context.Context, t *entities.Template) error {
+	body, err := json.Marshal(t.TemplateTask)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE templates SET project_id = $1, name = $2, body = $3, updated_at = NOW()
+		WHERE id = $4 RETURNING updated_at`
+	err = r.db.GetContext(ctx, &t.UpdatedAt, query, t.ProjectID, t.Name, body, t.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("template %d not found", t.ID), err)
+	}
+
+	return templateConflict(err, t.Name)
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM templates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("template %d not found", id), nil)
+	}
+
+	return nil
+}
+
+// templateConflict reports a name the user already gave to another
+// template.
+func templateConflict(err error, name string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("a template named %q already exists", name), err)
+	}
+
+	return err
+}
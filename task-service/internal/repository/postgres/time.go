@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// runningTimerIndex is the unique index allowing one running timer per
+// user.
+const runningTimerIndex = "idx_time_entries_running"
+
+type TimeRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewTimeRepository(db *sqlx.DB, l logger.ILogger) *TimeRepository {
+	return &TimeRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+func (r *TimeRepository) GetAllByTaskId(ctx context.Context, taskID int) ([]*entities.TimeEntry, error) {
+	query := "SELECT * FROM time_entries WHERE task_id = $1 ORDER BY started_at, id"
+	entries := []*entities.TimeEntry{}
+	err := r.db.SelectContext(ctx, &entries, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *TimeRepository) GetById(ctx context.Context, taskID int, id int64) (*entities.TimeEntry, error) {
+	query := "SELECT * FROM time_entries WHERE task_id = $1 AND id = $2"
+	entry := entities.TimeEntry{}
+	err := r.db.GetContext(ctx, &entry, query, taskID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("time entry %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetRunning returns the running timer of the user, nil if there is none.
+func (r *TimeRepository) GetRunning(ctx context.Context, userID int) (*entities.TimeEntry, error) {
+	query := "SELECT * FROM time_entries WHERE user_id = $1 AND ended_at IS NULL"
+	entry := entities.TimeEntry{}
+	err := r.db.GetContext(ctx, &entry, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Create inserts a finished entry, or a running timer when e.EndedAt is
+// nil, and updates the time spent on the task.
+func (r *TimeRepository) Create(ctx context.Context, e *entities.TimeEntry) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTimeSpent(ctx, tx, e.TaskID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO time_entries (task_id, user_id, started_at, ended_at, note)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, duration, created_at`
+	err = tx.QueryRowxContext(ctx, query, e.TaskID, e.UserID, e.StartedAt, e.EndedAt, e.Note).
+		Scan(&e.ID, &e.Duration, &e.CreatedAt)
+	if err != nil {
+		return timerConflict(err)
+	}
+
+	if err := updateTimeSpent(ctx, tx, e.TaskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Stop ends the user's timer on the task at the given time, or when it
+// started if that is later.
+func (r *TimeRepository) Stop(ctx context.Context, userID, taskID int, at time.Time) (*entities.TimeEntry, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTimeSpent(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE time_entries SET ended_at = GREATEST($3, started_at)
+		WHERE user_id = $1 AND task_id = $2 AND ended_at IS NULL RETURNING *`
+	entry := entities.TimeEntry{}
+	if err := tx.GetContext(ctx, &entry, query, userID, taskID, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("no timer is running on task %d", taskID), err)
+		}
+		return nil, err
+	}
+
+	if err := updateTimeSpent(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *TimeRepository) Update(ctx context.Context, e *entities.TimeEntry) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTimeSpent(ctx, tx, e.TaskID); err != nil {
+		return err
+	}
+
+	query := `UPDATE time_entries SET started_at = $1, ended_at = $2, note = $3
+		WHERE task_id = $4 AND id = $5 RETURNING duration`
+	if err := tx.GetContext(ctx, &e.Duration, query, e.StartedAt, e.EndedAt, e.Note, e.TaskID, e.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return app.NewAppError(app.ErrNotFound, fmt.Sprintf("time entry %d not found", e.ID), err)
+		}
+		return err
+	}
+
+	if err := updateTimeSpent(ctx, tx, e.TaskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TimeRepository) Delete(ctx context.Context, taskID int, id int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTimeSpent(ctx, tx, taskID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM time_entries WHERE task_id = $1 AND id = $2", taskID, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("time entry %d not found", id), nil)
+	}
+
+	if err := updateTimeSpent(ctx, tx, taskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Report sums up the part of the user's finished entries that falls into
+// the filter's range. Days are UTC and an entry counts for the day it
+// started. Entries on trashed tasks are still reported.
+func (r *TimeRepository) Report(ctx context.Context, f entities.TimeReportFilter) ([]*entities.TimeReportGroup, int64, error) {
+	args := []interface{}{f.UserID, f.From, f.To}
+	project := ""
+	if f.ProjectID != nil {
+		args = append(args, *f.ProjectID)
+		project = " AND t.project_id = $4"
+	}
+
+	base := `WITH e AS (
+			SELECT te.id, te.task_id, t.title, t.labels,
+				GREATEST(te.started_at, $2) AS started_at, LEAST(te.ended_at, $3) AS ended_at
+			FROM time_entries te JOIN tasks t ON t.id = te.task_id
+			WHERE te.user_id = $1 AND te.ended_at IS NOT NULL
+				AND te.started_at < $3 AND te.ended_at > $2` + project + `
+		)`
+	sum := "COALESCE(SUM(EXTRACT(EPOCH FROM e.ended_at - e.started_at)), 0)::BIGINT"
+
+	var query string
+	switch f.GroupBy {
+	case entities.GroupByDay:
+		query = base + `SELECT to_char(e.started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day,
+			` + sum + ` AS seconds, COUNT(*) AS entries
+			FROM e GROUP BY 1 ORDER BY 1`
+	case entities.GroupByTask:
+		query = base + `SELECT e.task_id, e.title, ` + sum + ` AS seconds, COUNT(*) AS entries
+			FROM e GROUP BY e.task_id, e.title ORDER BY seconds DESC, e.task_id`
+	case entities.GroupByLabel:
+		query = base + `SELECT l.label, ` + sum + ` AS seconds, COUNT(*) AS entries
+			FROM e LEFT JOIN LATERAL unnest(e.labels) AS l(label) ON TRUE
+			GROUP BY l.label ORDER BY l.label NULLS LAST`
+	default:
+		return nil, 0, fmt.Errorf("unknown grouping %q", f.GroupBy)
+	}
+
+	groups := []*entities.TimeReportGroup{}
+	if err := r.db.SelectContext(ctx, &groups, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Labels count an entry once per label, so the total is taken
+	// separately.
+	var total int64
+	if err := r.db.GetContext(ctx, &total, base+"SELECT "+sum+" FROM e", args...); err != nil {
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+// lockTimeSpent locks the task before its entries change. updateTimeSpent
+// sums the entries as the transaction sees them, so without the lock two
+// concurrent changes would each miss the other's entry.
+func lockTimeSpent(ctx context.Context, tx queryer, taskID int) error {
+	var id int
+	err := tx.GetContext(ctx, &id, "SELECT id FROM tasks WHERE id = $1 FOR UPDATE", taskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("task %d not found", taskID), err)
+	}
+
+	return err
+}
+
+// updateTimeSpent recomputes the total kept on the task. It is derived
+// data, so the task version stays as it is.
+func updateTimeSpent(ctx context.Context, tx queryer, taskID int) error {
+	query := `UPDATE tasks SET time_spent = (
+			SELECT COALESCE(SUM(duration), 0) FROM time_entries WHERE task_id = $1
+		) WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, taskID)
+	return err
+}
+
+// timerConflict turns a violation of the one running timer rule into a
+// conflict.
+func timerConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == runningTimerIndex {
+		return app.NewAppError(app.ErrConflict, "another timer is already running", err)
+	}
+
+	return err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/lib/pq"
+)
+
+// GetDeleted returns a task that is in the trash.
+func (r *TaskRepository) GetDeleted(ctx context.Context, id int) (*entities.Task, error) {
+	task := entities.Task{}
+	err := conn(ctx, r.db).GetContext(ctx, &task, "SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("task %d is not in the trash", id), err)
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// Restore takes the task out of the trash together with the subtasks that
+// were deleted along with it, recording the restore of each of them.
+func (r *TaskRepository) Restore(ctx context.Context, id, actorID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	task := entities.Task{}
+	err = tx.GetContext(ctx, &task, "SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return app.NewAppError(app.ErrNotFound, fmt.Sprintf("task %d is not in the trash", id), err)
+		}
+		return err
+	}
+
+	if task.ParentID != nil {
+		var parentDeleted bool
+		query := "SELECT deleted_at IS NOT NULL FROM tasks WHERE id = $1"
+		if err := tx.GetContext(ctx, &parentDeleted, query, *task.ParentID); err != nil {
+			return err
+		}
+		if parentDeleted {
+			return app.NewAppError(app.ErrConflict, fmt.Sprintf("parent task %d is in the trash, restore it first", *task.ParentID), nil)
+		}
+	}
+
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = $2
+		), restored AS (
+			UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id IN (SELECT id FROM subtree)
+		)
+		INSERT INTO task_events (task_id, actor_id, type) SELECT id, $3, $4 FROM subtree RETURNING id`
+	var ids []int64
+	if err := tx.SelectContext(ctx, &ids, query, id, *task.DeletedAt, actorID, entities.EventTaskRestored); err != nil {
+		return err
+	}
+
+	if err := eventsRecorded(ctx, tx, ids); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge permanently removes up to limit tasks that were moved to the trash
+// before the given time, oldest first, and returns the storage keys of the
+// attachments that went with them. Rows are claimed with SKIP LOCKED so
+// that replicas can purge concurrently.
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time, limit int) (int, []string, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	var ids pq.Int64Array
+	query := `SELECT COALESCE(array_agg(id), '{}') FROM (
+			SELECT id FROM tasks WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2 FOR UPDATE SKIP LOCKED
+		) expired`
+	if err := tx.GetContext(ctx, &ids, query, before, limit); err != nil {
+		return 0, nil, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	// Subtasks are removed by ON DELETE CASCADE, so their attachments are
+	// collected as well.
+	var keys []string
+	query = `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = ANY($1)
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		)
+		SELECT a.storage_key FROM task_attachments a JOIN subtree s ON s.id = a.task_id`
+	if err := tx.SelectContext(ctx, &keys, query, ids); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tasks WHERE id = ANY($1)", ids); err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+
+	return len(ids), keys, nil
+}
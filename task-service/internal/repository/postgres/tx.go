@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey carries the transaction of a unit of work through the context.
+type txKey struct{}
+
+type unitOfWork struct {
+	tx         *sqlx.Tx
+	savepoints int
+}
+
+// queryer is implemented by both *sqlx.DB and *sqlx.Tx.
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// txn is a transaction begun by a repository method. Within a unit of
+// work it is a savepoint of the outer transaction, so a failing write
+// only undoes itself.
+type txn struct {
+	*sqlx.Tx
+	savepoint string
+	done      bool
+}
+
+func begin(ctx context.Context, db *sqlx.DB) (*txn, error) {
+	if uow, ok := ctx.Value(txKey{}).(*unitOfWork); ok {
+		uow.savepoints++
+		name := fmt.Sprintf("sp_%d", uow.savepoints)
+		if _, err := uow.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+			return nil, err
+		}
+		return &txn{Tx: uow.tx, savepoint: name}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txn{Tx: tx}, nil
+}
+
+func (t *txn) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+
+	_, err := t.Tx.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	return err
+}
+
+// Rollback undoes the transaction unless it has been committed, which makes
+// it safe to defer right after begin.
+func (t *txn) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+
+	_, err := t.Tx.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint)
+	return err
+}
+
+// conn returns the transaction of the unit of work ctx belongs to, or db
+// outside of one.
+func conn(ctx context.Context, db *sqlx.DB) queryer {
+	if uow, ok := ctx.Value(txKey{}).(*unitOfWork); ok {
+		return uow.tx
+	}
+
+	return db
+}
+
+// runInTx runs fn in one transaction shared by every repository call made
+// with the context fn receives. Nested calls use savepoints, so an error
+// returned by a nested fn only undoes what that fn wrote.
+func runInTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) error {
+	tx, err := begin(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	inner := ctx
+	if tx.savepoint == "" {
+		inner = context.WithValue(ctx, txKey{}, &unitOfWork{tx: tx.Tx})
+	}
+
+	if err := fn(inner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RunInTx runs fn as a single unit of work, see runInTx.
+func (r *TaskRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTx(ctx, r.db, fn)
+}
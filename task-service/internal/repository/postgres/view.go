@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ViewRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewViewRepository(db *sqlx.DB, l logger.ILogger) *ViewRepository {
+	return &ViewRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+type viewRow struct {
+	entities.View
+	RawFilter []byte `db:"filter"`
+}
+
+func (row *viewRow) view() (*entities.View, error) {
+	v := row.View
+	if err := json.Unmarshal(row.RawFilter, &v.Filter); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (r *ViewRepository) Create(ctx context.Context, v *entities.View) error {
+	filter, err := json.Marshal(v.Filter)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO views (user_id, project_id, name, filter, sort) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+	err = r.db.QueryRowxContext(ctx, query, v.UserID, v.ProjectID, v.Name, filter, v.Sort).
+		Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
+	return viewConflict(err, v.Name)
+}
+
+func (r *ViewRepository) GetById(ctx context.Context, id int) (*entities.View, error) {
+	var row viewRow
+	if err := r.db.GetContext(ctx, &row, "SELECT * FROM views WHERE id = $1", id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("view %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return row.view()
+}
+
+// GetAllForUser returns the user's own views and those shared with them
+// through their projects.
+func (r *ViewRepository) GetAllForUser(ctx context.Context, userID int) ([]*entities.View, error) {
+	query := `SELECT * FROM views
+		WHERE user_id = $1 OR project_id IN (SELECT project_id FROM project_members WHERE user_id = $1)
+		ORDER BY name, id`
+	var rows []viewRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, err
+	}
+
+	views := make([]*entities.View, len(rows))
+	for i := range rows {
+		v, err := rows[i].view()
+		if err != nil {
+			return nil, err
+		}
+		views[i] = v
+	}
+
+	return views, nil
+}
+
+func (r *ViewRepository) Update(ctx context.Context, v *entities.View) error {
+	filter, err := json.Marshal(v.Filter)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE views SET project_id = $1, name = $2, filter = $3, sort = $4, updated_at = NOW()
+		WHERE id = $5 RETURNING updated_at`
+	err = r.db.GetContext(ctx, &v.UpdatedAt, query, v.ProjectID, v.Name, filter, v.Sort, v.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("view %d not found", v.ID), err)
+	}
+
+	return viewConflict(err, v.Name)
+}
+
+func (r *ViewRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM views WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("view %d not found", id), nil)
+	}
+
+	return nil
+}
+
+// viewConflict reports a name the user already gave to another view.
+func viewConflict(err error, name string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("a view named %q already exists", name), err)
+	}
+
+	return err
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db     *sqlx.DB
+	logger logger.ILogger
+}
+
+func NewWebhookRepository(db *sqlx.DB, l logger.ILogger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, w *entities.Webhook) error {
+	query := `INSERT INTO webhooks (user_id, url, secret, events, active) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	return r.db.QueryRowxContext(ctx, query, w.UserID, w.URL, w.Secret, w.Events, w.Active).Scan(&w.ID, &w.CreatedAt)
+}
+
+func (r *WebhookRepository) GetById(ctx context.Context, id int) (*entities.Webhook, error) {
+	var w entities.Webhook
+	if err := r.db.GetContext(ctx, &w, "SELECT * FROM webhooks WHERE id = $1", id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("webhook %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func (r *WebhookRepository) GetAllByUserId(ctx context.Context, userID int) ([]*entities.Webhook, error) {
+	webhooks := []*entities.Webhook{}
+	if err := r.db.SelectContext(ctx, &webhooks, "SELECT * FROM webhooks WHERE user_id = $1 ORDER BY id", userID); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, w *entities.Webhook) error {
+	query := "UPDATE webhooks SET url = $1, secret = $2, events = $3, active = $4 WHERE id = $5"
+	result, err := r.db.ExecContext(ctx, query, w.URL, w.Secret, w.Events, w.Active, w.ID)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("webhook %d not found", w.ID), nil)
+	}
+
+	return nil
+}
+
+// Delete removes the webhook along with its delivery log.
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return app.NewAppError(app.ErrNotFound, fmt.Sprintf("webhook %d not found", id), nil)
+	}
+
+	return nil
+}
+
+// GetDeliveries returns a page of the webhook's deliveries, newest first,
+// along with their total number. An empty status returns all of them.
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status string, limit, offset int) ([]*entities.WebhookDelivery, int, error) {
+	var total int
+	query := "SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1 AND ($2 = '' OR status = $2)"
+	if err := r.db.GetContext(ctx, &total, query, webhookID, status); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := []*entities.WebhookDelivery{}
+	query = `SELECT * FROM webhook_deliveries WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC LIMIT $3 OFFSET $4`
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, status, limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *entities.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+	return r.db.QueryRowxContext(ctx, query, d.WebhookID, d.EventID, d.EventType, []byte(d.Payload), d.Status,
+		d.NextAttemptAt).Scan(&d.ID, &d.CreatedAt)
+}
+
+// SaveDelivery records the outcome of an attempt.
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, d *entities.WebhookDelivery) error {
+	return saveDelivery(ctx, r.db, d)
+}
+
+func saveDelivery(ctx context.Context, q queryer, d *entities.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3,
+			response_status = $4, last_error = $5, delivered_at = $6
+		WHERE id = $7`
+	_, err := q.ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus,
+		d.LastError, d.DeliveredAt, d.ID)
+	return err
+}
+
+// Redeliver puts a delivery back in the queue with a fresh set of
+// attempts.
+func (r *WebhookRepository) Redeliver(ctx context.Context, webhookID int, id int64) (*entities.WebhookDelivery, error) {
+	var d entities.WebhookDelivery
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = 0, next_attempt_at = NOW()
+		WHERE id = $2 AND webhook_id = $3 RETURNING *`
+	if err := r.db.GetContext(ctx, &d, query, entities.DeliveryPending, id, webhookID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("delivery %d not found", id), err)
+		}
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+type dueDelivery struct {
+	entities.WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// ProcessDue hands up to limit pending deliveries whose next attempt is
+// due to deliver, which records the outcome on the delivery, and saves
+// them. The deliveries are claimed in a short transaction that locks them
+// with SKIP LOCKED and leases them by pushing next_attempt_at lease ahead,
+// so that replicas never send the same delivery concurrently; the claim is
+// committed before any request is made and every outcome is saved on its
+// own. A delivery whose replica dies mid-batch is retried once its lease
+// runs out. Deliveries of inactive webhooks wait until the webhook is
+// active again.
+func (r *WebhookRepository) ProcessDue(ctx context.Context, limit int, lease time.Duration,
+	deliver func(ctx context.Context, w *entities.Webhook, d *entities.WebhookDelivery)) (int, error) {
+	due, err := r.claimDue(ctx, limit, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range due {
+		d := &row.WebhookDelivery
+		deliver(ctx, &entities.Webhook{ID: d.WebhookID, URL: row.URL, Secret: row.Secret}, d)
+		if err := saveDelivery(ctx, r.db, d); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(due), nil
+}
+
+func (r *WebhookRepository) claimDue(ctx context.Context, limit int, lease time.Duration) ([]*dueDelivery, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT d.*, w.url, w.secret FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = $1 AND d.next_attempt_at <= NOW() AND w.active
+		ORDER BY d.next_attempt_at
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`
+	var due []*dueDelivery
+	if err := tx.SelectContext(ctx, &due, query, entities.DeliveryPending, limit); err != nil {
+		return nil, err
+	}
+
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(due))
+	for i, row := range due {
+		ids[i] = row.ID
+	}
+
+	query = "UPDATE webhook_deliveries SET next_attempt_at = NOW() + $1 * INTERVAL '1 second' WHERE id = ANY($2)"
+	if _, err := tx.ExecContext(ctx, query, int64(lease.Seconds()), pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return due, nil
+}
+
+// PurgeDeliveries removes finished deliveries older than before. Dead
+// ones are kept for as long as their webhook exists.
+func (r *WebhookRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE status = $1 AND created_at < $2",
+		entities.DeliveryDelivered, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// enqueueDeliveries queues the given events for every active webhook
+// subscribed to them whose owner can see the task. The payload is built
+// here, within the recording transaction, so that it shows the task as of
+// the event rather than as of the delivery.
+func enqueueDeliveries(ctx context.Context, tx queryer, ids []int64) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT w.id, e.id, e.type, jsonb_build_object(
+			'id', e.id,
+			'type', e.type,
+			'created_at', e.created_at,
+			'actor_id', e.actor_id,
+			'task_id', e.task_id,
+			'changes', e.changes,
+			'task', ` + taskSnapshot + `)
+		FROM task_events e
+		JOIN tasks t ON t.id = e.task_id
+		JOIN webhooks w ON w.active AND e.type = ANY(w.events)
+		WHERE e.id = ANY($1)
+		  AND ` + taskVisible("t", "w.user_id")
+	_, err := tx.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
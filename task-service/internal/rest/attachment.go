@@ -0,0 +1,199 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+// multipartOverhead is allowed on top of the file size for multipart
+// boundaries and part headers.
+const multipartOverhead = 1 << 20
+
+type AttachmentUseCase interface {
+	GetAll(ctx context.Context, userID, taskID int) ([]*entities.Attachment, error)
+	Upload(ctx context.Context, userID, taskID int, fileName, contentType string, r io.Reader) (*entities.Attachment, error)
+	Open(ctx context.Context, userID, taskID, id int) (*entities.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, taskID, id int) error
+}
+
+type AttachmentHandler struct {
+	Usecase AttachmentUseCase
+	maxSize int64
+	responder
+}
+
+func NewAttachmentHandler(m *mux.Router, uc AttachmentUseCase, maxSize int64, l logger.ILogger) {
+	handler := AttachmentHandler{
+		Usecase:   uc,
+		maxSize:   maxSize,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/tasks/{id:[0-9]+}/attachments", handler.GetAll).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/attachments", handler.Upload).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/attachments/{attachment_id:[0-9]+}", handler.Download).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/attachments/{attachment_id:[0-9]+}", handler.Delete).Methods("DELETE")
+}
+
+func (h *AttachmentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	attachments, err := h.Usecase.GetAll(r.Context(), userID, taskID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch attachments", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, attachments)
+}
+
+// Upload reads the "file" part of a multipart form and streams it to the
+// usecase without buffering it in memory.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartOverhead)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Expected a multipart/form-data body", "invalid_body")
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			h.writeError(w, http.StatusBadRequest, "The file part is missing", "missing_file")
+			return
+		}
+		if err != nil {
+			h.writeUploadError(w, err)
+			return
+		}
+
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.Usecase.Upload(r.Context(), userID, taskID, part.FileName(), part.Header.Get("Content-Type"), part)
+		part.Close()
+		if err != nil {
+			h.writeUploadError(w, err)
+			return
+		}
+
+		h.logger.Info("Attachment uploaded", "task_id", taskID, "attachment_id", attachment.ID, "size", attachment.Size)
+		h.writeJSON(w, http.StatusCreated, attachment)
+		return
+	}
+}
+
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	id, err := strconv.Atoi(vars["attachment_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid attachment ID", "invalid_id")
+		return
+	}
+
+	attachment, contents, err := h.Usecase.Open(r.Context(), userID, taskID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch attachment", "fetch_error")
+		return
+	}
+	defer contents.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, contents); err != nil {
+		h.logger.Error("Failed to stream attachment", "attachment_id", id, "error", err.Error())
+	}
+}
+
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	id, err := strconv.Atoi(vars["attachment_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid attachment ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, taskID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete attachment", "delete_error")
+		return
+	}
+
+	h.logger.Info("Attachment deleted", "task_id", taskID, "attachment_id", id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func (h *AttachmentHandler) writeUploadError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		h.writeError(w, http.StatusRequestEntityTooLarge, "Request body is too large", "too_large")
+		return
+	}
+
+	h.writeAppError(w, err, "Failed to upload attachment", "upload_error")
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/ical"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type CalendarUseCase interface {
+	GetToken(ctx context.Context, userID int) (*entities.CalendarToken, error)
+	RegenerateToken(ctx context.Context, userID int) (*entities.CalendarToken, error)
+	Feed(ctx context.Context, token string, fn func(t *entities.Task) error) error
+}
+
+type CalendarHandler struct {
+	Usecase CalendarUseCase
+	responder
+}
+
+// NewCalendarFeedHandler serves the feed itself. Calendar clients cannot
+// send a JWT, so it must be mounted outside the authenticated routes: the
+// token in the URL is the credential.
+func NewCalendarFeedHandler(m *mux.Router, uc CalendarUseCase, l logger.ILogger) {
+	handler := CalendarHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/calendar/{token:[0-9a-f]+}.ics", handler.Feed).Methods("GET")
+}
+
+// NewCalendarHandler serves the management of the feed token.
+func NewCalendarHandler(m *mux.Router, uc CalendarUseCase, l logger.ILogger) {
+	handler := CalendarHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/calendar/token", handler.GetToken).Methods("GET")
+	m.HandleFunc("/calendar/token", handler.RegenerateToken).Methods("POST")
+}
+
+func (h *CalendarHandler) GetToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	t, err := h.Usecase.GetToken(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch calendar token", "fetch_error")
+		return
+	}
+
+	t.Path = feedPath(t.Token)
+	h.writeJSON(w, http.StatusOK, t)
+}
+
+func (h *CalendarHandler) RegenerateToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	t, err := h.Usecase.RegenerateToken(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to regenerate calendar token", "update_error")
+		return
+	}
+
+	t.Path = feedPath(t.Token)
+	h.writeJSON(w, http.StatusCreated, t)
+}
+
+// Feed renders the calendar as VEVENTs, or as VTODOs with type=todo. The
+// whole document is rendered before answering so that its hash can serve
+// as the ETag: clients poll feeds often and mostly get a 304.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("type")
+	if kind == "" {
+		kind = ical.KindEvent
+	}
+	if kind != ical.KindEvent && kind != ical.KindTodo {
+		h.writeError(w, http.StatusBadRequest, "type must be event or todo", "invalid_type")
+		return
+	}
+
+	var body bytes.Buffer
+	enc := ical.NewTaskWriter(&body, kind)
+	err := h.Usecase.Feed(r.Context(), mux.Vars(r)["token"], enc.Encode)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		h.writeAppError(w, err, "Failed to render calendar", "calendar_error")
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if match := r.Header.Get("If-None-Match"); match != "" && noneMatch(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+func feedPath(token string) string {
+	return fmt.Sprintf("/calendar/%s.ics", token)
+}
@@ -0,0 +1,270 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/middleware"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsMaxMessage bounds the messages clients may send.
+	wsMaxMessage = 64 << 10
+	// wsPingInterval must stay below wsPongWait so that live clients
+	// always answer in time.
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsWriteWait    = 10 * time.Second
+	// wsCloseWait is how long the peer gets to answer our close.
+	wsCloseWait = 5 * time.Second
+
+	// wsProtocol is the subprotocol the server selects. Browser clients
+	// offer it together with their JWT as "bearer.<token>", since they
+	// cannot set the Authorization header on WebSocket requests.
+	wsProtocol    = "collab"
+	wsTokenPrefix = "bearer."
+)
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{wsProtocol},
+	// Clients authenticate with a token rather than a cookie, so another
+	// origin cannot connect on a user's behalf.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type CollabUseCase interface {
+	Connect(userID int) *entities.CollabClient
+	Disconnect(c *entities.CollabClient)
+	Subscribe(ctx context.Context, c *entities.CollabClient, topic string) ([]int, error)
+	Unsubscribe(c *entities.CollabClient, topic string)
+	Typing(c *entities.CollabClient, topic string, typing bool) error
+	Topics(c *entities.CollabClient, ev *entities.StreamEvent) []string
+}
+
+type CollabHandler struct {
+	Usecase CollabUseCase
+	Stream  StreamUseCase
+	responder
+}
+
+// NewCollabHandler serves the collaboration WebSocket. Browsers cannot set
+// headers on WebSocket requests, so the route authenticates by itself,
+// also accepting the JWT in the Sec-WebSocket-Protocol header, and must be
+// mounted outside the routes that require the Authorization header.
+func NewCollabHandler(m *mux.Router, uc CollabUseCase, stream StreamUseCase, l logger.ILogger) {
+	handler := CollabHandler{
+		Usecase:   uc,
+		Stream:    stream,
+		responder: responder{logger: l},
+	}
+
+	m.Handle("/ws", bearerFromProtocol(middleware.JwtPayloadMiddleware(l)(http.HandlerFunc(handler.Serve)))).Methods("GET")
+}
+
+// bearerFromProtocol moves a token offered as a "bearer.<token>"
+// subprotocol into the Authorization header. Unlike a query parameter the
+// header does not end up in access logs or browser history.
+func bearerFromProtocol(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			for _, protocol := range websocket.Subprotocols(r) {
+				if token, ok := strings.CutPrefix(protocol, wsTokenPrefix); ok && token != "" {
+					r.Header.Set("Authorization", "Bearer "+token)
+					break
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Serve upgrades the connection and then runs two loops: this goroutine
+// writes everything the client receives, another one reads its commands.
+// Replies to commands are passed to the writer so that frames are never
+// written concurrently out of order.
+func (h *CollabHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	// On failure the upgrader has already written an HTTP error.
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Warn("WebSocket upgrade failed", "user_id", userID, "error", err.Error())
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsMaxMessage)
+
+	// The request context is not tied to the hijacked connection.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, _, err := h.Stream.Subscribe(ctx, userID, 0)
+	if err != nil {
+		h.logger.Error("Failed to subscribe to task events", "user_id", userID, "error", err.Error())
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "cannot subscribe to task events"), time.Now().Add(wsWriteWait))
+		return
+	}
+	defer h.Stream.Unsubscribe(sub)
+
+	client := h.Usecase.Connect(userID)
+	defer h.Usecase.Disconnect(client)
+
+	h.logger.Info("WebSocket connected", "user_id", userID)
+
+	replies := make(chan *entities.CollabMessage, 16)
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		h.read(ctx, conn, client, replies)
+	}()
+
+	write := func(msg *entities.CollabMessage) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		return conn.WriteMessage(websocket.TextMessage, data)
+	}
+
+	closeWith := func(code int, reason string) {
+		message := websocket.FormatCloseMessage(code, reason)
+		if err := conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsWriteWait)); err != nil {
+			return
+		}
+		select {
+		case <-readDone:
+		case <-time.After(wsCloseWait):
+		}
+	}
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case <-readDone:
+			h.logger.Info("WebSocket disconnected", "user_id", userID)
+			return
+		case msg, ok := <-client.Send:
+			if !ok {
+				switch client.CloseReason {
+				case entities.CollabClosedShutdown:
+					closeWith(websocket.CloseGoingAway, "server shutting down")
+				default:
+					closeWith(websocket.CloseTryAgainLater, "client too slow")
+				}
+				return
+			}
+			err = write(msg)
+		case ev, ok := <-sub.Events:
+			if !ok {
+				closeWith(websocket.CloseTryAgainLater, "task event stream closed")
+				return
+			}
+			for _, topic := range h.Usecase.Topics(client, ev) {
+				if err = write(&entities.CollabMessage{Type: entities.CollabEvent, Topic: topic, Event: ev}); err != nil {
+					break
+				}
+			}
+		case msg := <-replies:
+			err = write(msg)
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+		}
+
+		if err != nil {
+			h.logger.Warn("WebSocket write failed", "user_id", userID, "error", err.Error())
+			return
+		}
+	}
+}
+
+// read handles the client's commands until the connection fails or is
+// closed.
+func (h *CollabHandler) read(ctx context.Context, conn *websocket.Conn, client *entities.CollabClient, replies chan<- *entities.CollabMessage) {
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	reply := func(msg *entities.CollabMessage) bool {
+		select {
+		case replies <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				h.logger.Debug("WebSocket read failed", "user_id", client.UserID, "error", err.Error())
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		var msg entities.CollabMessage
+		if messageType != websocket.TextMessage || json.Unmarshal(data, &msg) != nil {
+			if !reply(&entities.CollabMessage{Type: entities.CollabError, Error: "messages must be JSON objects", Code: "parse_error"}) {
+				return
+			}
+			continue
+		}
+
+		var response *entities.CollabMessage
+		switch msg.Type {
+		case entities.CollabSubscribe:
+			users, err := h.Usecase.Subscribe(ctx, client, msg.Topic)
+			if err != nil {
+				response = h.collabError(msg.Topic, err)
+			} else {
+				response = &entities.CollabMessage{Type: entities.CollabSubscribed, Topic: msg.Topic, Users: users}
+			}
+		case entities.CollabUnsubscribe:
+			h.Usecase.Unsubscribe(client, msg.Topic)
+			response = &entities.CollabMessage{Type: entities.CollabUnsubscribed, Topic: msg.Topic}
+		case entities.CollabTyping:
+			typing := msg.Typing == nil || *msg.Typing
+			if err := h.Usecase.Typing(client, msg.Topic, typing); err != nil {
+				response = h.collabError(msg.Topic, err)
+			}
+		default:
+			response = &entities.CollabMessage{Type: entities.CollabError, Error: "unknown message type", Code: "invalid_type"}
+		}
+
+		if response != nil && !reply(response) {
+			return
+		}
+	}
+}
+
+// collabError turns usecase errors into error messages, the way
+// writeAppError does for HTTP responses.
+func (h *CollabHandler) collabError(topic string, err error) *entities.CollabMessage {
+	var appErr *app.AppError
+	if errors.As(err, &appErr) {
+		return &entities.CollabMessage{Type: entities.CollabError, Topic: topic, Error: appErr.Message, Code: string(appErr.Type)}
+	}
+
+	h.logger.Error("Collaboration command failed", "topic", topic, "error", err.Error())
+	return &entities.CollabMessage{Type: entities.CollabError, Topic: topic, Error: "internal error", Code: string(app.ErrInternal)}
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	var dto entities.CreateDependencyDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	if dto.BlockedByID == 0 {
+		h.writeError(w, http.StatusBadRequest, "blocked_by_id is required", "missing_blocked_by_id")
+		return
+	}
+
+	err = h.Usecase.AddDependency(r.Context(), userID, id, dto.BlockedByID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to add dependency", "dependency_error")
+		return
+	}
+
+	h.logger.Info("Dependency added", "task_id", id, "blocked_by_id", dto.BlockedByID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func (h *TaskHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	blockerID, err := strconv.Atoi(vars["blocker_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid blocker ID", "invalid_id")
+		return
+	}
+
+	err = h.Usecase.RemoveDependency(r.Context(), userID, id, blockerID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to remove dependency", "dependency_error")
+		return
+	}
+
+	h.logger.Info("Dependency removed", "task_id", id, "blocked_by_id", blockerID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func (h *TaskHandler) GetGraph(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	graph, err := h.Usecase.GetGraph(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to build dependency graph", "graph_error")
+		return
+	}
+
+	body, err := json.Marshal(graph)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Error marshalling response", "marshal_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
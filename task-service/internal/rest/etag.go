@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// taskETag derives a strong entity tag from the task version. Progress and
+// time spent change without a new version, so they are part of the tag as
+// well to keep conditional reads fresh.
+func taskETag(t *entities.Task) string {
+	tag := strconv.Itoa(t.Version)
+	if t.Progress != nil {
+		tag += "-" + strconv.FormatFloat(*t.Progress, 'f', -1, 64)
+	}
+	if t.TimeSpent > 0 {
+		tag += "-t" + strconv.FormatInt(t.TimeSpent, 10)
+	}
+
+	return `"` + tag + `"`
+}
+
+// etagVersions returns the task versions the tags of an If-Match header
+// refer to, skipping tags that can never match such as weak or foreign
+// ones. any reports a "*", which matches every version.
+func etagVersions(header string) (versions []int, any bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		tag = tag[1 : len(tag)-1]
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			tag = tag[:i]
+		}
+
+		version, err := strconv.Atoi(tag)
+		if err != nil || version < 1 || slices.Contains(versions, version) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, false
+}
+
+// noneMatch reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 prescribes for it.
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestETagVersions(t *testing.T) {
+	cases := []struct {
+		header   string
+		versions []int
+		any      bool
+	}{
+		{`"3"`, []int{3}, false},
+		{`"3-0.5-t60"`, []int{3}, false},
+		{`"3", "4-0.5"`, []int{3, 4}, false},
+		{`"3","3-0.5"`, []int{3}, false},
+		{`W/"3", "x", "0", "5"`, []int{5}, false},
+		{`W/"3"`, nil, false},
+		{`*`, nil, true},
+		{`"3", *`, nil, true},
+	}
+	for _, c := range cases {
+		versions, any := etagVersions(c.header)
+		if !reflect.DeepEqual(versions, c.versions) || any != c.any {
+			t.Errorf("etagVersions(%s) = %v, %v, want %v, %v", c.header, versions, any, c.versions, c.any)
+		}
+	}
+}
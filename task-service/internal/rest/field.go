@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/gorilla/mux"
+)
+
+func (h *ProjectHandler) GetFields(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	fields, err := h.Usecase.GetFields(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch project fields", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, fields)
+}
+
+func (h *ProjectHandler) CreateField(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	var field entities.ProjectField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	field.ProjectID = id
+	if err := h.Usecase.CreateField(r.Context(), userID, &field); err != nil {
+		h.writeAppError(w, err, "Failed to create project field", "create_error")
+		return
+	}
+
+	h.logger.Info("Project field created", "project_id", id, "field_id", field.ID, "name", field.Name)
+	h.writeJSON(w, http.StatusCreated, field)
+}
+
+// UpdateField changes the options of a select field. Name and type may be
+// repeated in the body but not changed.
+func (h *ProjectHandler) UpdateField(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	fieldID, err := strconv.Atoi(vars["field_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid field ID", "invalid_id")
+		return
+	}
+
+	var field entities.ProjectField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	field.ID, field.ProjectID = fieldID, id
+	if err := h.Usecase.UpdateField(r.Context(), userID, &field); err != nil {
+		h.writeAppError(w, err, "Failed to update project field", "update_error")
+		return
+	}
+
+	h.logger.Info("Project field updated", "project_id", id, "field_id", fieldID)
+	h.writeJSON(w, http.StatusOK, field)
+}
+
+// DeleteField removes the field and its values from every task of the
+// project.
+func (h *ProjectHandler) DeleteField(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	fieldID, err := strconv.Atoi(vars["field_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid field ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.DeleteField(r.Context(), userID, id, fieldID); err != nil {
+		h.writeAppError(w, err, "Failed to delete project field", "delete_error")
+		return
+	}
+
+	h.logger.Info("Project field deleted", "project_id", id, "field_id", fieldID)
+	w.WriteHeader(http.StatusNoContent)
+}
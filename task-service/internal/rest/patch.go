@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+// parseMergePatch reads an RFC 7396 merge patch. Tasks are flat, so every
+// member of the patch document names one field.
+func parseMergePatch(body []byte) (*entities.TaskPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+
+	return &entities.TaskPatch{Fields: fields}, nil
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parseJSONPatch reads an RFC 6902 JSON Patch limited to the top-level
+// task fields. add and replace set a field, remove clears it and test
+// checks its value; operations apply in order, so a test sees the effect
+// of the operations before it.
+func parseJSONPatch(body []byte) (*entities.TaskPatch, error) {
+	var ops []patchOperation
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return nil, errors.New("JSON patch must be an array of operations")
+	}
+
+	patch := entities.TaskPatch{
+		Operations: make([]entities.TaskPatchOperation, 0, len(ops)),
+	}
+
+	for i, op := range ops {
+		field := strings.TrimPrefix(op.Path, "/")
+		if field == op.Path || field == "" || strings.Contains(field, "/") {
+			return nil, fmt.Errorf("operation %d: path %q does not name a task field", i, op.Path)
+		}
+
+		operation := entities.TaskPatchOperation{Field: field, Value: op.Value}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("operation %d: %s requires a value", i, op.Op)
+			}
+			operation.Test = op.Op == "test"
+		case "remove":
+			operation.Value = json.RawMessage("null")
+		default:
+			return nil, fmt.Errorf("operation %d: unsupported op %q", i, op.Op)
+		}
+		patch.Operations = append(patch.Operations, operation)
+	}
+
+	return &patch, nil
+}
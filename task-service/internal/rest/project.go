@@ -0,0 +1,270 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type ProjectUseCase interface {
+	GetAll(ctx context.Context, userID int) ([]*entities.Project, error)
+	GetById(ctx context.Context, userID, id int) (*entities.Project, error)
+	Create(ctx context.Context, dto *entities.CreateProjectDto) (*entities.Project, error)
+	Update(ctx context.Context, userID int, p *entities.Project) error
+	Delete(ctx context.Context, userID, id int) error
+	GetMembers(ctx context.Context, userID, projectID int) ([]*entities.ProjectMember, error)
+	SetMember(ctx context.Context, userID int, m *entities.ProjectMember) error
+	RemoveMember(ctx context.Context, userID, projectID, memberID int) error
+	GetFields(ctx context.Context, userID, projectID int) ([]*entities.ProjectField, error)
+	CreateField(ctx context.Context, userID int, f *entities.ProjectField) error
+	UpdateField(ctx context.Context, userID int, f *entities.ProjectField) error
+	DeleteField(ctx context.Context, userID, projectID, id int) error
+}
+
+type ProjectHandler struct {
+	Usecase ProjectUseCase
+	responder
+}
+
+func NewProjectHandler(m *mux.Router, uc ProjectUseCase, l logger.ILogger) {
+	handler := ProjectHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/projects", handler.GetAll).Methods("GET")
+	m.HandleFunc("/projects", handler.Create).Methods("POST")
+	m.HandleFunc("/projects/{id:[0-9]+}", handler.GetById).Methods("GET")
+	m.HandleFunc("/projects/{id:[0-9]+}", handler.Update).Methods("PUT")
+	m.HandleFunc("/projects/{id:[0-9]+}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/projects/{id:[0-9]+}/members", handler.GetMembers).Methods("GET")
+	m.HandleFunc("/projects/{id:[0-9]+}/members/{user_id:[0-9]+}", handler.SetMember).Methods("PUT")
+	m.HandleFunc("/projects/{id:[0-9]+}/members/{user_id:[0-9]+}", handler.RemoveMember).Methods("DELETE")
+	m.HandleFunc("/projects/{id:[0-9]+}/fields", handler.GetFields).Methods("GET")
+	m.HandleFunc("/projects/{id:[0-9]+}/fields", handler.CreateField).Methods("POST")
+	m.HandleFunc("/projects/{id:[0-9]+}/fields/{field_id:[0-9]+}", handler.UpdateField).Methods("PUT")
+	m.HandleFunc("/projects/{id:[0-9]+}/fields/{field_id:[0-9]+}", handler.DeleteField).Methods("DELETE")
+}
+
+func (h *ProjectHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	projects, err := h.Usecase.GetAll(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch projects", "fetch_error")
+		return
+	}
+
+	if projects == nil {
+		projects = []*entities.Project{}
+	}
+
+	h.writeJSON(w, http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	project, err := h.Usecase.GetById(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch project", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, project)
+}
+
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var dto entities.CreateProjectDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	dto.OwnerID = userID
+
+	project, err := h.Usecase.Create(r.Context(), &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to create project", "create_error")
+		return
+	}
+
+	h.logger.Info("Project created", "user_id", userID, "project_id", project.ID)
+	h.writeJSON(w, http.StatusCreated, project)
+}
+
+func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	var project entities.Project
+	if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	project.ID = id
+	if err := h.Usecase.Update(r.Context(), userID, &project); err != nil {
+		h.writeAppError(w, err, "Failed to update project", "update_error")
+		return
+	}
+
+	h.logger.Info("Project updated", "project_id", id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete project", "delete_error")
+		return
+	}
+
+	h.logger.Info("Project deleted", "project_id", id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func (h *ProjectHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	members, err := h.Usecase.GetMembers(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch members", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, members)
+}
+
+func (h *ProjectHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	memberID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID", "invalid_id")
+		return
+	}
+
+	var member entities.ProjectMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	member.ProjectID = id
+	member.UserID = memberID
+	if err := h.Usecase.SetMember(r.Context(), userID, &member); err != nil {
+		h.writeAppError(w, err, "Failed to set project member", "member_error")
+		return
+	}
+
+	h.logger.Info("Project member set", "project_id", id, "user_id", memberID, "role", member.Role)
+	h.writeJSON(w, http.StatusOK, member)
+}
+
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+		return
+	}
+
+	memberID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid user ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.RemoveMember(r.Context(), userID, id, memberID); err != nil {
+		h.writeAppError(w, err, "Failed to remove project member", "member_error")
+		return
+	}
+
+	h.logger.Info("Project member removed", "project_id", id, "user_id", memberID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
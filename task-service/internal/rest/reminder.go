@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type ReminderUseCase interface {
+	GetSettings(ctx context.Context, userID int) (*entities.ReminderSettings, error)
+	UpdateSettings(ctx context.Context, s *entities.ReminderSettings) error
+}
+
+type ReminderHandler struct {
+	Usecase ReminderUseCase
+	responder
+}
+
+func NewReminderHandler(m *mux.Router, uc ReminderUseCase, l logger.ILogger) {
+	handler := ReminderHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/reminders/settings", handler.GetSettings).Methods("GET")
+	m.HandleFunc("/reminders/settings", handler.UpdateSettings).Methods("PUT")
+}
+
+func (h *ReminderHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	settings, err := h.Usecase.GetSettings(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch reminder settings", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, settings)
+}
+
+func (h *ReminderHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var settings entities.ReminderSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	settings.UserID = userID
+	if err := h.Usecase.UpdateSettings(r.Context(), &settings); err != nil {
+		h.writeAppError(w, err, "Failed to update reminder settings", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, settings)
+}
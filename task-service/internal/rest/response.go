@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// responder holds the response helpers shared by all handlers.
+type responder struct {
+	logger logger.ILogger
+}
+
+// writeAppError maps usecase errors onto HTTP statuses, falling back to a
+// 500 with the given message and code for anything unexpected.
+func (h *responder) writeAppError(w http.ResponseWriter, err error, message, code string) {
+	var appErr *app.AppError
+
+	if errors.As(err, &appErr) {
+		switch appErr.Type {
+		case app.ErrNotFound:
+			h.writeError(w, http.StatusNotFound, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrInvalidInput:
+			h.writeError(w, http.StatusBadRequest, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrConflict:
+			h.writeError(w, http.StatusConflict, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrUnauthorized:
+			h.writeError(w, http.StatusForbidden, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrTooLarge:
+			h.writeError(w, http.StatusRequestEntityTooLarge, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrUnsupported:
+			h.writeError(w, http.StatusUnsupportedMediaType, appErr.Message, string(appErr.Type))
+			return
+		case app.ErrPrecondition:
+			h.writeError(w, http.StatusPreconditionFailed, appErr.Message, string(appErr.Type))
+			return
+		}
+	}
+
+	h.logger.Error(message, "error", err.Error())
+	h.writeError(w, http.StatusInternalServerError, message, code)
+}
+
+func (h *responder) writeError(w http.ResponseWriter, status int, message, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error: message,
+		Code:  code,
+	})
+	h.logger.Error("Request failed", "status", status, "message", message, "code", code)
+}
+
+func (h *responder) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Error marshalling response", "marshal_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
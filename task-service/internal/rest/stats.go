@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type StatsUseCase interface {
+	Get(ctx context.Context, f entities.StatsFilter) (*entities.Stats, error)
+}
+
+type StatsHandler struct {
+	Usecase StatsUseCase
+	responder
+}
+
+func NewStatsHandler(m *mux.Router, uc StatsUseCase, l logger.ILogger) {
+	handler := StatsHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/stats", handler.Get).Methods("GET")
+}
+
+// Get takes an optional project_id and a from/to range given like the
+// time report's.
+func (h *StatsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := entities.StatsFilter{UserID: userID}
+
+	var err error
+	if filter.From, err = parseReportTime(query.Get("from"), false); err != nil {
+		h.writeError(w, http.StatusBadRequest, "from must be an RFC 3339 time or a date", "invalid_from")
+		return
+	}
+	if filter.To, err = parseReportTime(query.Get("to"), true); err != nil {
+		h.writeError(w, http.StatusBadRequest, "to must be an RFC 3339 time or a date", "invalid_to")
+		return
+	}
+
+	if v := query.Get("project_id"); v != "" {
+		projectID, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+			return
+		}
+		filter.ProjectID = &projectID
+	}
+
+	stats, err := h.Usecase.Get(r.Context(), filter)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to compute statistics", "stats_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
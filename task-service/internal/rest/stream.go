@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+// streamRetry is the reconnection delay suggested to clients, in
+// milliseconds.
+const streamRetry = 3000
+
+type StreamUseCase interface {
+	Subscribe(ctx context.Context, userID int, lastEventID int64) (*entities.Subscription, []*entities.StreamEvent, error)
+	Unsubscribe(sub *entities.Subscription)
+}
+
+type StreamHandler struct {
+	Usecase   StreamUseCase
+	heartbeat time.Duration
+	responder
+}
+
+func NewStreamHandler(m *mux.Router, uc StreamUseCase, heartbeat time.Duration, l logger.ILogger) {
+	handler := StreamHandler{
+		Usecase:   uc,
+		heartbeat: heartbeat,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/tasks/stream", handler.Stream).Methods("GET")
+}
+
+// Stream pushes task events as Server-Sent Events named after the event
+// type, with the event id as SSE id. Clients resume with the Last-Event-ID
+// header, or the last_event_id query parameter for the first connection
+// of an EventSource, which cannot set headers. A comment is sent every
+// heartbeat to keep proxies from closing an idle stream.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var after int64
+	if lastEventID != "" {
+		var err error
+		after, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || after < 0 {
+			h.writeError(w, http.StatusBadRequest, "Last-Event-ID must be an event id", "invalid_event_id")
+			return
+		}
+	}
+
+	sub, backlog, err := h.Usecase.Subscribe(r.Context(), userID, after)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to subscribe to task events", "stream_error")
+		return
+	}
+	defer h.Usecase.Unsubscribe(sub)
+
+	// The server write timeout would end the stream after 15 seconds, so
+	// the deadline is pushed back before every write instead.
+	rc := http.NewResponseController(w)
+	extend := func() error {
+		return rc.SetWriteDeadline(time.Now().Add(2 * h.heartbeat))
+	}
+	if err := extend(); err != nil {
+		h.logger.Error("Cannot extend write deadline for stream", "error", err.Error())
+		h.writeError(w, http.StatusInternalServerError, "Streaming is not supported", "stream_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+
+	send := func(ev *entities.StreamEvent) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if err := extend(); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	replayed := make(map[int]bool, len(backlog))
+	for _, ev := range backlog {
+		if err := send(ev); err != nil {
+			return
+		}
+		replayed[ev.ID] = true
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	h.logger.Info("Task stream opened", "user_id", userID, "last_event_id", after, "replayed", len(backlog))
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			h.logger.Info("Task stream closed by client", "user_id", userID)
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				h.logger.Info("Task stream closed", "user_id", userID)
+				return
+			}
+			if replayed[ev.ID] {
+				continue
+			}
+			if err := send(ev); err != nil {
+				h.logger.Warn("Task stream write failed", "user_id", userID, "error", err.Error())
+				return
+			}
+		case <-ticker.C:
+			if err := extend(); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
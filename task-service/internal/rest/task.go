@@ -3,45 +3,94 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
 	"github.com/dielit66/task-management-system/internal/logger"
 	"github.com/dielit66/task-management-system/internal/middleware"
 	"github.com/gorilla/mux"
 )
 
 type TaskUseCase interface {
-	GetAllById(ctx context.Context, id int) ([]*entities.Task, error)
-	GetById(ctx context.Context, id int) (*entities.Task, error)
-	Create(ctx context.Context, t *entities.CreateTaskDto) error
-	Update(ctx context.Context, t *entities.Task) error
-	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, f entities.TaskFilter) ([]*entities.Task, error)
+	GetById(ctx context.Context, userID, id int) (*entities.Task, error)
+	Create(ctx context.Context, t *entities.CreateTaskDto) (*entities.Task, error)
+	Update(ctx context.Context, userID int, t *entities.Task, scope string) error
+	Patch(ctx context.Context, userID, id, version int, p *entities.TaskPatch, scope string) (*entities.Task, error)
+	Delete(ctx context.Context, userID, id, version int) error
+	GetSubtasks(ctx context.Context, userID, id int, tree bool) ([]*entities.Task, error)
+	AddDependency(ctx context.Context, userID, taskID, blockedByID int) error
+	RemoveDependency(ctx context.Context, userID, taskID, blockedByID int) error
+	GetGraph(ctx context.Context, userID, id int) (*entities.TaskGraph, error)
+	Move(ctx context.Context, userID, id, version int, dto *entities.MoveTaskDto) (*entities.Task, error)
+	Focus(ctx context.Context, userID, limit int) ([]*entities.Task, error)
+	GetHistory(ctx context.Context, userID, id, limit, offset int) (*entities.TaskHistory, error)
+	Bulk(ctx context.Context, userID int, req *entities.BulkRequest) (*entities.BulkResponse, error)
+	Trash(ctx context.Context, userID int) ([]*entities.Task, error)
+	Restore(ctx context.Context, userID, id int) (*entities.Task, error)
+	Export(ctx context.Context, f entities.TaskFilter, fn func(t *entities.Task) error) error
+	Import(ctx context.Context, userID int, r io.Reader, format string, mapping map[string]string, dryRun bool) (*entities.ImportReport, error)
 }
 
+const (
+	defaultFocusLimit = 5
+	maxFocusLimit     = 50
+
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
 type TaskHandler struct {
 	Usecase TaskUseCase
-	logger  logger.ILogger
+	// requireIfMatch rejects writes that are not conditional on a version.
+	requireIfMatch bool
+	responder
 }
 
-func NewTaskHandler(m *mux.Router, uc TaskUseCase, l logger.ILogger) {
+func NewTaskHandler(m *mux.Router, uc TaskUseCase, requireIfMatch bool, l logger.ILogger) {
 	handler := TaskHandler{
-		Usecase: uc,
-		logger:  l,
+		Usecase:        uc,
+		requireIfMatch: requireIfMatch,
+		responder:      responder{logger: l},
 	}
 
 	m.HandleFunc("/tasks", handler.GetAllByUserId).Methods("GET")
 	m.HandleFunc("/tasks", handler.Create).Methods("POST")
+	m.HandleFunc("/tasks/focus", handler.Focus).Methods("GET")
+	m.HandleFunc("/tasks/bulk", handler.Bulk).Methods("POST")
+	m.HandleFunc("/tasks/trash", handler.Trash).Methods("GET")
+	m.HandleFunc("/tasks/export", handler.Export).Methods("GET")
+	m.HandleFunc("/tasks/import", handler.Import).Methods("POST")
 	m.HandleFunc("/tasks/{id:[0-9]+}", handler.GetById).Methods("GET")
 	m.HandleFunc("/tasks/{id:[0-9]+}", handler.Update).Methods("PUT")
+	m.HandleFunc("/tasks/{id:[0-9]+}", handler.Patch).Methods("PATCH")
 	m.HandleFunc("/tasks/{id:[0-9]+}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/tasks/{id:[0-9]+}/subtasks", handler.GetSubtasks).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/dependencies", handler.AddDependency).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/dependencies/{blocker_id:[0-9]+}", handler.RemoveDependency).Methods("DELETE")
+	m.HandleFunc("/tasks/{id:[0-9]+}/graph", handler.GetGraph).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/move", handler.Move).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/history", handler.GetHistory).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/restore", handler.Restore).Methods("POST")
+	m.HandleFunc("/projects/{project_id:[0-9]+}/tasks", handler.GetAllByUserId).Methods("GET")
 
 	m.Use(middleware.JwtPayloadMiddleware(l))
 }
 
 func (h *TaskHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -49,9 +98,16 @@ func (h *TaskHandler) GetById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.Usecase.GetById(context.Background(), id)
+	task, err := h.Usecase.GetById(r.Context(), userID, id)
 	if err != nil {
-		h.writeError(w, http.StatusNotFound, "Task not found", "not_found")
+		h.writeAppError(w, err, "Failed to fetch task", "fetch_error")
+		return
+	}
+
+	etag := taskETag(task)
+	w.Header().Set("ETag", etag)
+	if inm := r.Header.Get("If-None-Match"); inm != "" && noneMatch(inm, etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -73,11 +129,23 @@ func (h *TaskHandler) GetAllByUserId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := h.parseFilter(w, r, userID)
+	if !ok {
+		return
+	}
+
+	sort, err := entities.ParseTaskSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		h.writeFilterError(w, err)
+		return
+	}
+	filter.Sort = sort
+
 	h.logger.Debug("Fetching tasks for user", "user_id", userID)
-	tasks, err := h.Usecase.GetAllById(context.Background(), userID)
+	tasks, err := h.Usecase.List(context.Background(), filter)
 	if err != nil {
 		h.logger.Error("Failed to fetch tasks", "user_id", userID, "error", err)
-		h.writeError(w, http.StatusInternalServerError, "Failed to fetch tasks", "fetch_error")
+		h.writeAppError(w, err, "Failed to fetch tasks", "fetch_error")
 		return
 	}
 
@@ -93,6 +161,35 @@ func (h *TaskHandler) GetAllByUserId(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+// parseFilter reads the task filter from the query string and the route.
+// It answers the request itself and returns false when the filter is
+// invalid.
+func (h *TaskHandler) parseFilter(w http.ResponseWriter, r *http.Request, userID int) (entities.TaskFilter, bool) {
+	query := r.URL.Query()
+	if projectID := mux.Vars(r)["project_id"]; projectID != "" {
+		query.Set("project_id", projectID)
+	}
+
+	filter, err := entities.ParseTaskFilter(query, userID)
+	if err != nil {
+		h.writeFilterError(w, err)
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// writeFilterError answers 400 for filter grammar errors.
+func (h *TaskHandler) writeFilterError(w http.ResponseWriter, err error) {
+	var filterErr *entities.FilterError
+	if errors.As(err, &filterErr) {
+		h.writeError(w, http.StatusBadRequest, filterErr.Message, filterErr.Code)
+		return
+	}
+
+	h.writeAppError(w, err, "Invalid filter", "invalid_filter")
+}
+
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -124,21 +221,33 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	dto.UserID = userID
 
-	err = h.Usecase.Create(context.Background(), &dto)
+	task, err := h.Usecase.Create(context.Background(), &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to create task", "create_error")
+		return
+	}
+
+	body, err = json.Marshal(task)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Failed to create task", "create_error")
+		h.writeError(w, http.StatusInternalServerError, "Error marshalling response", "marshal_error")
 		return
 	}
 
-	h.logger.Info("Task created", "user_id", userID)
+	h.logger.Info("Task created", "user_id", userID, "task_id", task.ID)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("id"))
+	w.Write(body)
 }
 
 func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -164,20 +273,106 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scope := r.URL.Query().Get("scope")
+	if scope != "" && scope != entities.ScopeThis && scope != entities.ScopeFuture {
+		h.writeError(w, http.StatusBadRequest, "scope must be \"this\" or \"future\"", "invalid_scope")
+		return
+	}
+
+	version, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
 	task.ID = id
-	err = h.Usecase.Update(context.Background(), &task)
+	task.Version = version
+	err = h.Usecase.Update(context.Background(), userID, &task, scope)
+	if err != nil {
+		h.writeWriteError(w, r, err, id, "Failed to update task", "update_error")
+		return
+	}
+
+	// The tag has to match what a GET returns, which includes progress and
+	// time spent, so it is taken from the stored task.
+	updated, err := h.Usecase.GetById(context.Background(), userID, id)
 	if err != nil {
-		h.writeError(w, http.StatusNotFound, "Task not found", "not_found")
+		h.writeAppError(w, err, "Failed to fetch task", "fetch_error")
 		return
 	}
 
 	h.logger.Info("Task updated", "task_id", id)
+	w.Header().Set("ETag", taskETag(updated))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"success"}`))
 }
 
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error reading request body", "read_error")
+		return
+	}
+
+	var patch *entities.TaskPatch
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case mergePatchType, "application/json", "":
+		patch, err = parseMergePatch(body)
+	case jsonPatchType:
+		patch, err = parseJSONPatch(body)
+	default:
+		h.writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Content-Type must be %s or %s", mergePatchType, jsonPatchType), "unsupported_media_type")
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error(), "parse_error")
+		return
+	}
+
+	scope := r.URL.Query().Get("scope")
+	if scope != "" && scope != entities.ScopeThis && scope != entities.ScopeFuture {
+		h.writeError(w, http.StatusBadRequest, "scope must be \"this\" or \"future\"", "invalid_scope")
+		return
+	}
+
+	version, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.Usecase.Patch(r.Context(), userID, id, version, patch, scope)
+	if err != nil {
+		h.writeWriteError(w, r, err, id, "Failed to update task", "update_error")
+		return
+	}
+
+	h.logger.Info("Task patched", "task_id", id, "version", task.Version)
+	w.Header().Set("ETag", taskETag(task))
+	h.writeJSON(w, http.StatusOK, task)
+}
+
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -185,9 +380,14 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.Usecase.Delete(context.Background(), id)
+	version, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	err = h.Usecase.Delete(context.Background(), userID, id, version)
 	if err != nil {
-		h.writeError(w, http.StatusNotFound, "Task not found", "not_found")
+		h.writeWriteError(w, r, err, id, "Failed to delete task", "delete_error")
 		return
 	}
 
@@ -197,17 +397,281 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"status":"success"}`))
 }
 
-func (h *TaskHandler) writeError(w http.ResponseWriter, status int, message, code string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Error: message,
-		Code:  code,
-	})
-	h.logger.Error("Request failed", "status", status, "message", message, "code", code)
+// ifMatch returns the version of task id that the If-Match header names, 0
+// when the write may apply to any version and -1 when no tag can match.
+// Of a list naming several versions it returns the current one if listed.
+// It answers the request itself and returns false when the header is
+// required but missing or the task cannot be read.
+func (h *TaskHandler) ifMatch(w http.ResponseWriter, r *http.Request, id int) (int, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if h.requireIfMatch {
+			h.writeError(w, http.StatusPreconditionRequired, "If-Match header with the task ETag is required", "precondition_required")
+			return 0, false
+		}
+		return 0, true
+	}
+
+	versions, any := etagVersions(header)
+	switch {
+	case any:
+		return 0, true
+	case len(versions) == 0:
+		return -1, true
+	case len(versions) == 1:
+		return versions[0], true
+	}
+
+	// The write checks the version again, so a change in between fails it
+	// like any other stale tag.
+	userID, _ := r.Context().Value("userID").(int)
+	current, err := h.Usecase.GetById(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch task", "fetch_error")
+		return 0, false
+	}
+
+	if slices.Contains(versions, current.Version) {
+		return current.Version, true
+	}
+
+	return versions[0], true
+}
+
+// writeWriteError reports a failed write. Version conflicts are answered
+// with the current representation of the task so the client can merge.
+func (h *TaskHandler) writeWriteError(w http.ResponseWriter, r *http.Request, err error, id int, message, code string) {
+	var appErr *app.AppError
+	if !errors.As(err, &appErr) || appErr.Type != app.ErrPrecondition {
+		h.writeAppError(w, err, message, code)
+		return
+	}
+
+	userID, _ := r.Context().Value("userID").(int)
+	current, getErr := h.Usecase.GetById(r.Context(), userID, id)
+	if getErr != nil {
+		h.writeAppError(w, getErr, message, code)
+		return
+	}
+
+	h.logger.Info("Rejected write of a modified task", "task_id", id, "version", current.Version)
+	w.Header().Set("ETag", taskETag(current))
+	h.writeJSON(w, http.StatusPreconditionFailed, current)
+}
+
+// Bulk answers 200 when the operations were committed and 422 when an
+// atomic request was rolled back; the body carries the per-item results
+// either way.
+func (h *TaskHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var req entities.BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	resp, err := h.Usecase.Bulk(r.Context(), userID, &req)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to run bulk operations", "bulk_error")
+		return
+	}
+
+	status := http.StatusOK
+	if !resp.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+
+	h.logger.Info("Bulk operations processed", "user_id", userID, "operations", len(resp.Results), "committed", resp.Committed)
+	h.writeJSON(w, status, resp)
+}
+
+func (h *TaskHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	tasks, err := h.Usecase.Trash(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch deleted tasks", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	task, err := h.Usecase.Restore(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to restore task", "restore_error")
+		return
+	}
+
+	h.logger.Info("Task restored", "task_id", id)
+	w.Header().Set("ETag", taskETag(task))
+	h.writeJSON(w, http.StatusOK, task)
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code"`
+func (h *TaskHandler) Focus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	limit := defaultFocusLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxFocusLimit {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxFocusLimit), "invalid_limit")
+			return
+		}
+		limit = n
+	}
+
+	tasks, err := h.Usecase.Focus(r.Context(), userID, limit)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch tasks", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) Move(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	var dto entities.MoveTaskDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	version, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.Usecase.Move(r.Context(), userID, id, version, &dto)
+	if err != nil {
+		h.writeWriteError(w, r, err, id, "Failed to move task", "move_error")
+		return
+	}
+
+	h.logger.Info("Task moved", "task_id", id, "status_id", task.StatusID, "rank", task.Rank)
+	w.Header().Set("ETag", taskETag(task))
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultHistoryLimit
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxHistoryLimit), "invalid_limit")
+			return
+		}
+		limit = n
+	}
+
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		n, err := strconv.Atoi(o)
+		if err != nil || n < 0 {
+			h.writeError(w, http.StatusBadRequest, "offset must be a non-negative integer", "invalid_offset")
+			return
+		}
+		offset = n
+	}
+
+	history, err := h.Usecase.GetHistory(r.Context(), userID, id, limit, offset)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch task history", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, history)
+}
+
+func (h *TaskHandler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return
+	}
+
+	tree := r.URL.Query().Get("tree") == "true"
+
+	tasks, err := h.Usecase.GetSubtasks(r.Context(), userID, id, tree)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch subtasks", "fetch_error")
+		return
+	}
+
+	if tasks == nil {
+		tasks = []*entities.Task{}
+	}
+
+	body, err := json.Marshal(tasks)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Error marshalling response", "marshal_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
 }
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type TemplateUseCase interface {
+	GetAll(ctx context.Context, userID int) ([]*entities.Template, error)
+	Get(ctx context.Context, userID, id int) (*entities.Template, error)
+	Create(ctx context.Context, userID int, dto *entities.TemplateDto) (*entities.Template, error)
+	Update(ctx context.Context, userID, id int, dto *entities.TemplateDto) (*entities.Template, error)
+	Delete(ctx context.Context, userID, id int) error
+	Instantiate(ctx context.Context, userID, id int, dto *entities.InstantiateTemplateDto) (*entities.Task, error)
+}
+
+type TemplateHandler struct {
+	Usecase TemplateUseCase
+	responder
+}
+
+func NewTemplateHandler(m *mux.Router, uc TemplateUseCase, l logger.ILogger) {
+	handler := TemplateHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/templates", handler.GetAll).Methods("GET")
+	m.HandleFunc("/templates", handler.Create).Methods("POST")
+	m.HandleFunc("/templates/{id:[0-9]+}", handler.Get).Methods("GET")
+	m.HandleFunc("/templates/{id:[0-9]+}", handler.Update).Methods("PUT")
+	m.HandleFunc("/templates/{id:[0-9]+}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/templates/{id:[0-9]+}/instantiate", handler.Instantiate).Methods("POST")
+}
+
+func (h *TemplateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	templates, err := h.Usecase.GetAll(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch templates", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.templateVars(w, r)
+	if !ok {
+		return
+	}
+
+	template, err := h.Usecase.Get(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch template", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var dto entities.TemplateDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	template, err := h.Usecase.Create(r.Context(), userID, &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to create template", "create_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, id, ok := h.templateVars(w, r)
+	if !ok {
+		return
+	}
+
+	var dto entities.TemplateDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	template, err := h.Usecase.Update(r.Context(), userID, id, &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to update template", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.templateVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete template", "delete_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+// Instantiate creates the template's tasks and answers with the top task,
+// its subtasks nested as children. The body is optional when the template
+// has no placeholders.
+func (h *TemplateHandler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, id, ok := h.templateVars(w, r)
+	if !ok {
+		return
+	}
+
+	var dto entities.InstantiateTemplateDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && err != io.EOF {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	task, err := h.Usecase.Instantiate(r.Context(), userID, id, &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to instantiate template", "create_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, task)
+}
+
+func (h *TemplateHandler) templateVars(w http.ResponseWriter, r *http.Request) (userID, id int, ok bool) {
+	userID, ok = r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return 0, 0, false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid template ID", "invalid_id")
+		return 0, 0, false
+	}
+
+	return userID, id, true
+}
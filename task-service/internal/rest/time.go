@@ -0,0 +1,280 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type TimeUseCase interface {
+	GetAll(ctx context.Context, userID, taskID int) ([]*entities.TimeEntry, error)
+	GetRunning(ctx context.Context, userID int) (*entities.TimeEntry, error)
+	Start(ctx context.Context, userID, taskID int, note string) (*entities.TimeEntry, error)
+	Stop(ctx context.Context, userID, taskID int) (*entities.TimeEntry, error)
+	Create(ctx context.Context, userID, taskID int, dto entities.TimeEntryDto) (*entities.TimeEntry, error)
+	Update(ctx context.Context, userID, taskID int, id int64, dto entities.TimeEntryDto) (*entities.TimeEntry, error)
+	Delete(ctx context.Context, userID, taskID int, id int64) error
+	Report(ctx context.Context, f entities.TimeReportFilter) (*entities.TimeReport, error)
+}
+
+type TimeHandler struct {
+	Usecase TimeUseCase
+	responder
+}
+
+func NewTimeHandler(m *mux.Router, uc TimeUseCase, l logger.ILogger) {
+	handler := TimeHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/tasks/{id:[0-9]+}/time", handler.GetAll).Methods("GET")
+	m.HandleFunc("/tasks/{id:[0-9]+}/time", handler.Create).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/time/start", handler.Start).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/time/stop", handler.Stop).Methods("POST")
+	m.HandleFunc("/tasks/{id:[0-9]+}/time/{entry_id:[0-9]+}", handler.Update).Methods("PUT")
+	m.HandleFunc("/tasks/{id:[0-9]+}/time/{entry_id:[0-9]+}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/time/running", handler.GetRunning).Methods("GET")
+	m.HandleFunc("/reports/time", handler.Report).Methods("GET")
+}
+
+func (h *TimeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.Usecase.GetAll(r.Context(), userID, taskID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch time entries", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entries)
+}
+
+// GetRunning answers 204 when the user has no running timer.
+func (h *TimeHandler) GetRunning(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	entry, err := h.Usecase.GetRunning(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch running timer", "fetch_error")
+		return
+	}
+
+	if entry == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entry)
+}
+
+// Start takes an optional {"note": "..."} body.
+func (h *TimeHandler) Start(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	entry, err := h.Usecase.Start(r.Context(), userID, taskID, body.Note)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to start timer", "start_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.Usecase.Stop(r.Context(), userID, taskID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to stop timer", "stop_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	var dto entities.TimeEntryDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	entry, err := h.Usecase.Create(r.Context(), userID, taskID, dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to create time entry", "create_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["entry_id"], 10, 64)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid time entry ID", "invalid_id")
+		return
+	}
+
+	var dto entities.TimeEntryDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	entry, err := h.Usecase.Update(r.Context(), userID, taskID, id, dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to update time entry", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, taskID, ok := h.taskVars(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["entry_id"], 10, 64)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid time entry ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, taskID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete time entry", "delete_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+// Report takes from and to as RFC 3339 times or dates. A date as to
+// includes that whole day, so from=2024-01-01&to=2024-01-07 covers a week.
+func (h *TimeHandler) Report(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := entities.TimeReportFilter{
+		UserID:  userID,
+		GroupBy: query.Get("group_by"),
+	}
+
+	var err error
+	if filter.From, err = parseReportTime(query.Get("from"), false); err != nil {
+		h.writeError(w, http.StatusBadRequest, "from must be an RFC 3339 time or a date", "invalid_from")
+		return
+	}
+	if filter.To, err = parseReportTime(query.Get("to"), true); err != nil {
+		h.writeError(w, http.StatusBadRequest, "to must be an RFC 3339 time or a date", "invalid_to")
+		return
+	}
+
+	if v := query.Get("project_id"); v != "" {
+		projectID, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid project ID", "invalid_id")
+			return
+		}
+		filter.ProjectID = &projectID
+	}
+
+	report, err := h.Usecase.Report(r.Context(), filter)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to build time report", "report_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+func (h *TimeHandler) taskVars(w http.ResponseWriter, r *http.Request) (userID, taskID int, ok bool) {
+	userID, ok = r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return 0, 0, false
+	}
+
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid task ID", "invalid_id")
+		return 0, 0, false
+	}
+
+	return userID, taskID, true
+}
+
+// parseReportTime parses an RFC 3339 time or a UTC date, which as the end
+// of a range stands for the end of that day. Empty values give zero.
+func parseReportTime(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/transfer"
+)
+
+const (
+	// maxImportSize bounds the body of an import request.
+	maxImportSize = 10 << 20
+	// exportWriteTimeout replaces the server write timeout for exports,
+	// which stream for as long as there are tasks to write.
+	exportWriteTimeout = 5 * time.Minute
+)
+
+func (h *TaskHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = entities.FormatJSON
+	}
+	if transfer.ContentType(format) == "" {
+		h.writeError(w, http.StatusBadRequest, "format must be csv, json, ndjson or ics", "invalid_format")
+		return
+	}
+
+	filter, ok := h.parseFilter(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		h.logger.Warn("Cannot extend write deadline for export", "error", err.Error())
+	}
+
+	// Headers are only sent with the first task, so that errors found
+	// before anything was written still get a proper status.
+	var enc transfer.Encoder
+	start := func() error {
+		w.Header().Set("Content-Type", transfer.ContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+
+		var err error
+		enc, err = transfer.NewEncoder(w, format)
+		return err
+	}
+
+	count := 0
+	err := h.Usecase.Export(r.Context(), filter, func(t *entities.Task) error {
+		if enc == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		count++
+		return enc.Encode(t)
+	})
+	if err == nil && enc == nil {
+		err = start()
+	}
+	if err == nil {
+		err = enc.Close()
+	}
+
+	if err != nil {
+		if enc == nil {
+			h.writeAppError(w, err, "Failed to export tasks", "export_error")
+			return
+		}
+		h.logger.Error("Export aborted", "user_id", userID, "written", count, "error", err.Error())
+		return
+	}
+
+	h.logger.Info("Tasks exported", "user_id", userID, "format", format, "count", count)
+}
+
+// Import answers 200 when the tasks were created, or would have been on a
+// dry run, and 422 when any row failed; the body is the import report.
+func (h *TaskHandler) Import(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = importFormat(r.Header.Get("Content-Type"))
+	}
+	if transfer.ContentType(format) == "" {
+		h.writeError(w, http.StatusBadRequest, "format must be csv, json, ndjson or ics", "invalid_format")
+		return
+	}
+
+	mapping := make(map[string]string)
+	for _, m := range query["map"] {
+		column, field, ok := strings.Cut(m, ":")
+		if !ok || column == "" {
+			h.writeError(w, http.StatusBadRequest, "map must be given as column:field", "invalid_mapping")
+			return
+		}
+		mapping[column] = field
+	}
+
+	dryRun := query.Get("dry_run") == "true"
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	report, err := h.Usecase.Import(r.Context(), userID, body, format, mapping, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("imports are limited to %d bytes", maxImportSize), "too_large")
+			return
+		}
+		h.writeAppError(w, err, "Failed to import tasks", "import_error")
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+
+	h.logger.Info("Tasks imported", "user_id", userID, "format", format, "rows", report.Total,
+		"errors", len(report.Errors), "dry_run", dryRun, "committed", report.Committed)
+	h.writeJSON(w, status, report)
+}
+
+// importFormat derives the import format from the request media type.
+func importFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/csv":
+		return entities.FormatCSV
+	case "application/json":
+		return entities.FormatJSON
+	case "application/x-ndjson":
+		return entities.FormatNDJSON
+	case "text/calendar":
+		return entities.FormatICS
+	}
+
+	return ""
+}
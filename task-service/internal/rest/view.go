@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+type ViewUseCase interface {
+	GetAll(ctx context.Context, userID int) ([]*entities.View, error)
+	Get(ctx context.Context, userID, id int) (*entities.View, error)
+	Create(ctx context.Context, userID int, dto *entities.ViewDto) (*entities.View, error)
+	Update(ctx context.Context, userID, id int, dto *entities.ViewDto) (*entities.View, error)
+	Delete(ctx context.Context, userID, id int) error
+	Tasks(ctx context.Context, userID, id int) ([]*entities.Task, error)
+}
+
+type ViewHandler struct {
+	Usecase ViewUseCase
+	responder
+}
+
+func NewViewHandler(m *mux.Router, uc ViewUseCase, l logger.ILogger) {
+	handler := ViewHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/views", handler.GetAll).Methods("GET")
+	m.HandleFunc("/views", handler.Create).Methods("POST")
+	m.HandleFunc("/views/{id:[0-9]+}", handler.Get).Methods("GET")
+	m.HandleFunc("/views/{id:[0-9]+}", handler.Update).Methods("PUT")
+	m.HandleFunc("/views/{id:[0-9]+}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/views/{id:[0-9]+}/tasks", handler.Tasks).Methods("GET")
+}
+
+func (h *ViewHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	views, err := h.Usecase.GetAll(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch views", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, views)
+}
+
+func (h *ViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.viewVars(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.Usecase.Get(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch view", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, view)
+}
+
+func (h *ViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var dto entities.ViewDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	view, err := h.Usecase.Create(r.Context(), userID, &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to create view", "create_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, view)
+}
+
+func (h *ViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, id, ok := h.viewVars(w, r)
+	if !ok {
+		return
+	}
+
+	var dto entities.ViewDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	view, err := h.Usecase.Update(r.Context(), userID, id, &dto)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to update view", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, view)
+}
+
+func (h *ViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.viewVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete view", "delete_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+// Tasks lists the tasks of the view as GET /tasks would with its filter
+// and sort.
+func (h *ViewHandler) Tasks(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.viewVars(w, r)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.Usecase.Tasks(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch tasks", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (h *ViewHandler) viewVars(w http.ResponseWriter, r *http.Request) (userID, id int, ok bool) {
+	userID, ok = r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return 0, 0, false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid view ID", "invalid_id")
+		return 0, 0, false
+	}
+
+	return userID, id, true
+}
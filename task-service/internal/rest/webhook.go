@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type WebhookUseCase interface {
+	GetAll(ctx context.Context, userID int) ([]*entities.Webhook, error)
+	Get(ctx context.Context, userID, id int) (*entities.Webhook, error)
+	Create(ctx context.Context, w *entities.Webhook) error
+	Update(ctx context.Context, userID int, w *entities.Webhook) error
+	Delete(ctx context.Context, userID, id int) error
+	GetDeliveries(ctx context.Context, userID, id int, status string, limit, offset int) (*entities.WebhookDeliveries, error)
+	Redeliver(ctx context.Context, userID, id int, deliveryID int64) (*entities.WebhookDelivery, error)
+	Test(ctx context.Context, userID, id int) (*entities.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	Usecase WebhookUseCase
+	responder
+}
+
+func NewWebhookHandler(m *mux.Router, uc WebhookUseCase, l logger.ILogger) {
+	handler := WebhookHandler{
+		Usecase:   uc,
+		responder: responder{logger: l},
+	}
+
+	m.HandleFunc("/webhooks", handler.GetAll).Methods("GET")
+	m.HandleFunc("/webhooks", handler.Create).Methods("POST")
+	m.HandleFunc("/webhooks/{id}", handler.Get).Methods("GET")
+	m.HandleFunc("/webhooks/{id}", handler.Update).Methods("PUT")
+	m.HandleFunc("/webhooks/{id}", handler.Delete).Methods("DELETE")
+	m.HandleFunc("/webhooks/{id}/test", handler.Test).Methods("POST")
+	m.HandleFunc("/webhooks/{id}/deliveries", handler.GetDeliveries).Methods("GET")
+	m.HandleFunc("/webhooks/{id}/deliveries/{delivery_id}/redeliver", handler.Redeliver).Methods("POST")
+}
+
+func (h *WebhookHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	webhooks, err := h.Usecase.GetAll(r.Context(), userID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch webhooks", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	webhook, err := h.Usecase.Get(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch webhook", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	var dto entities.WebhookDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	webhook := dto.Webhook()
+	webhook.UserID = userID
+	if err := h.Usecase.Create(r.Context(), webhook); err != nil {
+		h.writeAppError(w, err, "Failed to create webhook", "create_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	var dto entities.WebhookDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Error parsing request body", "parse_error")
+		return
+	}
+
+	webhook := dto.Webhook()
+	webhook.ID = id
+	if err := h.Usecase.Update(r.Context(), userID, webhook); err != nil {
+		h.writeAppError(w, err, "Failed to update webhook", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, id); err != nil {
+		h.writeAppError(w, err, "Failed to delete webhook", "delete_error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Test answers with the logged delivery of the test event, whether the
+// receiver accepted it or not.
+func (h *WebhookHandler) Test(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	delivery, err := h.Usecase.Test(r.Context(), userID, id)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to send test event", "test_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultDeliveriesLimit
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxDeliveriesLimit {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxDeliveriesLimit), "invalid_limit")
+			return
+		}
+		limit = n
+	}
+
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		n, err := strconv.Atoi(o)
+		if err != nil || n < 0 {
+			h.writeError(w, http.StatusBadRequest, "offset must be a non-negative integer", "invalid_offset")
+			return
+		}
+		offset = n
+	}
+
+	deliveries, err := h.Usecase.GetDeliveries(r.Context(), userID, id, query.Get("status"), limit, offset)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to fetch webhook deliveries", "fetch_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "User not authenticated", "unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid webhook ID", "invalid_id")
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(vars["delivery_id"], 10, 64)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid delivery ID", "invalid_id")
+		return
+	}
+
+	delivery, err := h.Usecase.Redeliver(r.Context(), userID, id, deliveryID)
+	if err != nil {
+		h.writeAppError(w, err, "Failed to redeliver", "update_error")
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, delivery)
+}
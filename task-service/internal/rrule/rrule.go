@@ -0,0 +1,200 @@
+// Package rrule implements the subset of iCalendar recurrence rules
+// (RFC 5545, section 3.3.10) supported for recurring tasks: FREQ of DAILY,
+// WEEKLY or MONTHLY with INTERVAL, BYDAY, COUNT and UNTIL.
+package rrule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10". An
+// optional "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch v := strings.ToUpper(value); v {
+			case Daily, Weekly, Monthly:
+				r.Freq = v
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY value %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+			// Weeks always start on Monday.
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+
+	if r.Freq == Monthly && len(r.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=MONTHLY")
+	}
+
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, v, time.UTC); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", v)
+}
+
+// Occurrence returns the n-th occurrence (1-based) of the rule starting at
+// dtstart, dtstart itself being the first one. ok is false when the rule
+// ends before that occurrence.
+func (r *Rule) Occurrence(dtstart time.Time, n int) (t time.Time, ok bool) {
+	if r.Count > 0 && n > r.Count {
+		return time.Time{}, false
+	}
+
+	t = dtstart
+	for i := 1; i < n; i++ {
+		if t, ok = r.next(dtstart, t); !ok {
+			return time.Time{}, false
+		}
+	}
+
+	if !r.Until.IsZero() && t.After(r.Until) {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// next returns the first occurrence strictly after prev. ok is false when
+// BYDAY can never be matched, e.g. every 7 days on a different weekday.
+func (r *Rule) next(dtstart, prev time.Time) (t time.Time, ok bool) {
+	switch r.Freq {
+	case Daily:
+		t = prev
+		for i := 0; i < 7; i++ {
+			t = t.AddDate(0, 0, r.Interval)
+			if r.matchesDay(t) {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval), true
+		}
+		// Look for a later matching day in the current week first, then
+		// jump to the Monday of the next week in the interval.
+		t = prev.AddDate(0, 0, 1)
+		for t.Weekday() != time.Monday {
+			if r.matchesDay(t) {
+				return t, true
+			}
+			t = t.AddDate(0, 0, 1)
+		}
+		t = t.AddDate(0, 0, 7*(r.Interval-1))
+		for !r.matchesDay(t) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true
+	default:
+		// Months that do not have the start day are skipped, as in RFC 5545.
+		day := dtstart.Day()
+		year, month, _ := prev.Date()
+		for {
+			month += time.Month(r.Interval)
+			t = time.Date(year, month, day, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+			if t.Day() == day {
+				return t, true
+			}
+		}
+	}
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, d := range r.ByDay {
+		if t.Weekday() == d {
+			return true
+		}
+	}
+
+	return false
+}
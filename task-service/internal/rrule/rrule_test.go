@@ -0,0 +1,132 @@
+package rrule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	r, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,we;COUNT=10")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := &Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Wednesday}, Count: 10}
+	if !reflect.DeepEqual(r, want) {
+		t.Fatalf("Parse = %+v, want %+v", r, want)
+	}
+
+	r, err = Parse("FREQ=DAILY;UNTIL=20260310")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if until := time.Date(2026, 3, 10, 23, 59, 59, 0, time.UTC); !r.Until.Equal(until) {
+		t.Fatalf("UNTIL of a date = %v, want the end of that day", r.Until)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"COUNT=3",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260310",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func occurrences(t *testing.T, rule string, dtstart time.Time, n int) []time.Time {
+	t.Helper()
+
+	r, err := Parse(rule)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", rule, err)
+	}
+
+	var got []time.Time
+	for i := 1; i <= n; i++ {
+		o, ok := r.Occurrence(dtstart, i)
+		if !ok {
+			break
+		}
+		got = append(got, o)
+	}
+
+	return got
+}
+
+func days(year int, month time.Month, hour int, ds ...int) []time.Time {
+	var out []time.Time
+	for _, d := range ds {
+		out = append(out, time.Date(year, month, d, hour, 0, 0, 0, time.UTC))
+	}
+
+	return out
+}
+
+func TestOccurrence(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		rule    string
+		dtstart time.Time
+		n       int
+		want    []time.Time
+	}{
+		{"FREQ=DAILY", monday, 3, days(2026, 3, 9, 2, 3, 4)},
+		{"FREQ=DAILY;INTERVAL=3", monday, 3, days(2026, 3, 9, 2, 5, 8)},
+		{"FREQ=WEEKLY", monday, 3, days(2026, 3, 9, 2, 9, 16)},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", monday, 4, days(2026, 3, 9, 2, 4, 9, 11)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", monday, 4, days(2026, 3, 9, 2, 4, 16, 18)},
+		// weekdays only: Friday is followed by Monday
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", friday, 2, days(2026, 3, 9, 6, 9)},
+		{"FREQ=DAILY;COUNT=3", monday, 5, days(2026, 3, 9, 2, 3, 4)},
+		{"FREQ=DAILY;UNTIL=20260304", monday, 5, days(2026, 3, 9, 2, 3, 4)},
+		{"FREQ=DAILY;UNTIL=20260304T090000Z", monday, 5, days(2026, 3, 9, 2, 3, 4)},
+		{"FREQ=DAILY;UNTIL=20260304T085959Z", monday, 5, days(2026, 3, 9, 2, 3)},
+		{"FREQ=MONTHLY;COUNT=2", monday, 5, []time.Time{monday, monday.AddDate(0, 1, 0)}},
+	}
+	for _, c := range cases {
+		if got := occurrences(t, c.rule, c.dtstart, c.n); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s from %v = %v, want %v", c.rule, c.dtstart, got, c.want)
+		}
+	}
+}
+
+func TestOccurrenceMonthEnd(t *testing.T) {
+	// Months without a 31st are skipped, and the day does not drift to
+	// the 28th after February.
+	dtstart := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	want := []time.Time{
+		dtstart,
+		time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 5, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 7, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 8, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC),
+	}
+	if got := occurrences(t, "FREQ=MONTHLY", dtstart, len(want)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("monthly on the 31st = %v, want %v", got, want)
+	}
+
+	// Every year on February 29th, as a monthly rule with an interval.
+	leap := time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)
+	got := occurrences(t, "FREQ=MONTHLY;INTERVAL=12", leap, 2)
+	if want := []time.Time{leap, time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("yearly on February 29th = %v, want %v", got, want)
+	}
+}
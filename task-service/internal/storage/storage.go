@@ -0,0 +1,18 @@
+// Package storage keeps attachment contents outside of the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs under string keys. Implementations stream
+// contents and never hold a whole blob in memory.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
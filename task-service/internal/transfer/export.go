@@ -0,0 +1,157 @@
+// Package transfer reads and writes tasks in the CSV, JSON, NDJSON and
+// iCalendar formats used for import and export.
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/ical"
+)
+
+// Columns are the CSV columns, in export order. Imports accept the same
+// names.
+var Columns = []string{"id", "title", "description", "status", "priority", "deadline",
+	"assignee_id", "project_id", "parent_id", "labels", "created_at"}
+
+// labelSeparator joins labels within a single CSV field.
+const labelSeparator = ";"
+
+var statusCodes = map[int]string{
+	entities.StatusNew:        "new",
+	entities.StatusInProgress: "in_progress",
+	entities.StatusCompleted:  "completed",
+}
+
+// Encoder writes tasks one at a time. Close must be called to finish the
+// document.
+type Encoder interface {
+	Encode(t *entities.Task) error
+	Close() error
+}
+
+// ContentType returns the media type of the format, empty for unknown ones.
+func ContentType(format string) string {
+	switch format {
+	case entities.FormatCSV:
+		return "text/csv; charset=utf-8"
+	case entities.FormatJSON:
+		return "application/json"
+	case entities.FormatNDJSON:
+		return "application/x-ndjson"
+	case entities.FormatICS:
+		return "text/calendar; charset=utf-8"
+	}
+
+	return ""
+}
+
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case entities.FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(Columns); err != nil {
+			return nil, err
+		}
+		return &csvEncoder{w: cw}, nil
+	case entities.FormatJSON:
+		return &jsonEncoder{w: w}, nil
+	case entities.FormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case entities.FormatICS:
+		return ical.NewTaskWriter(w, ical.KindTodo), nil
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Encode(t *entities.Task) error {
+	deadline := ""
+	if !t.Deadline.IsZero() {
+		deadline = t.Deadline.Format(time.RFC3339)
+	}
+
+	return e.w.Write([]string{
+		strconv.Itoa(t.ID),
+		t.Title,
+		t.Description,
+		statusCodes[t.StatusID],
+		t.Priority,
+		deadline,
+		optionalID(t.AssigneeID),
+		optionalID(t.ProjectID),
+		optionalID(t.ParentID),
+		strings.Join(t.Labels, labelSeparator),
+		t.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func optionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*id)
+}
+
+// jsonEncoder writes a single array, element by element.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(t *entities.Task) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	sep := ","
+	if e.count == 0 {
+		sep = "["
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(body)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "]"
+	if e.count == 0 {
+		end = "[]"
+	}
+
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(t *entities.Task) error {
+	return e.enc.Encode(t)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/ical"
+)
+
+// decodeICS reads every VTODO and VEVENT of the calendars in r as a row.
+// The deadline is the due date of a VTODO and the start of a VEVENT.
+func decodeICS(r io.Reader, maxRows int) ([]*entities.ImportRow, error) {
+	calendars, err := ical.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading iCalendar: %w", err)
+	}
+
+	var rows []*entities.ImportRow
+	for _, calendar := range calendars {
+		if calendar.Name != "VCALENDAR" {
+			return nil, fmt.Errorf("unexpected %s component, expected VCALENDAR", calendar.Name)
+		}
+
+		for _, c := range calendar.Components {
+			if c.Name != "VTODO" && c.Name != "VEVENT" {
+				continue
+			}
+			if len(rows) == maxRows {
+				return nil, ErrTooManyRows
+			}
+
+			row := &entities.ImportRow{Row: len(rows) + 1}
+			rows = append(rows, row)
+			if err := setComponent(row, c); err != nil {
+				row.Error = err.Error()
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func setComponent(row *entities.ImportRow, c *ical.Component) error {
+	if p, ok := c.Get("SUMMARY"); ok {
+		row.Task.Title = ical.UnescapeText(p.Value)
+	}
+	if p, ok := c.Get("DESCRIPTION"); ok {
+		row.Task.Description = ical.UnescapeText(p.Value)
+	}
+
+	deadline := "DTSTART"
+	if c.Name == "VTODO" {
+		deadline = "DUE"
+	}
+	if p, ok := c.Get(deadline); ok {
+		t, err := ical.ParseTime(p)
+		if err != nil {
+			return fmt.Errorf("deadline: %w", err)
+		}
+		row.Task.Deadline = t
+	}
+
+	if p, ok := c.Get("STATUS"); ok {
+		if row.StatusID = ical.StatusID(p.Value); row.StatusID == 0 && c.Name == "VTODO" {
+			return fmt.Errorf("status: unknown status %q", p.Value)
+		}
+	}
+
+	if p, ok := c.Get("PRIORITY"); ok {
+		value, err := strconv.Atoi(strings.TrimSpace(p.Value))
+		if err != nil || value < 0 || value > 9 {
+			return fmt.Errorf("priority: %q is not between 0 and 9", p.Value)
+		}
+		row.Task.Priority = ical.Priority(value)
+	}
+
+	for _, p := range c.Props {
+		if p.Name == "CATEGORIES" {
+			row.Task.Labels = append(row.Task.Labels, ical.SplitList(p.Value)...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// ErrTooManyRows is returned when a file holds more rows than allowed.
+var ErrTooManyRows = errors.New("too many rows")
+
+// importFields are the task fields an import can set. Other columns, such
+// as the id and creation time of an export, are ignored: imported tasks
+// always get new ids and are created at the top level.
+var importFields = map[string]bool{
+	"title":       true,
+	"description": true,
+	"status":      true,
+	"priority":    true,
+	"deadline":    true,
+	"assignee_id": true,
+	"project_id":  true,
+	"labels":      true,
+}
+
+// deadlineLayouts are tried in order when reading deadlines.
+var deadlineLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// CheckMapping validates a column mapping from source column names to task
+// fields.
+func CheckMapping(mapping map[string]string) error {
+	for column, field := range mapping {
+		if !importFields[field] {
+			return fmt.Errorf("column %q is mapped to unknown field %q", column, field)
+		}
+	}
+
+	return nil
+}
+
+// Decode reads up to maxRows rows in the given format. Columns are matched
+// to fields through mapping first and by name otherwise; iCalendar files
+// have no columns and ignore the mapping. Rows that cannot
+// be read are returned with their Error set; only a malformed document as
+// a whole fails Decode.
+func Decode(r io.Reader, format string, mapping map[string]string, maxRows int) ([]*entities.ImportRow, error) {
+	switch format {
+	case entities.FormatCSV:
+		return decodeCSV(r, mapping, maxRows)
+	case entities.FormatJSON:
+		return decodeJSON(r, mapping, maxRows, true)
+	case entities.FormatNDJSON:
+		return decodeJSON(r, mapping, maxRows, false)
+	case entities.FormatICS:
+		return decodeICS(r, maxRows)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func fieldOf(column string, mapping map[string]string) string {
+	if field, ok := mapping[column]; ok {
+		return field
+	}
+
+	field := strings.ToLower(strings.TrimSpace(column))
+	if importFields[field] {
+		return field
+	}
+
+	return ""
+}
+
+func decodeCSV(r io.Reader, mapping map[string]string, maxRows int) ([]*entities.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	fields := make([]string, len(header))
+	for i, column := range header {
+		fields[i] = fieldOf(column, mapping)
+	}
+
+	var rows []*entities.ImportRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+
+		row := &entities.ImportRow{Row: len(rows) + 1}
+		rows = append(rows, row)
+
+		var parseErr *csv.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			row.Error = parseErr.Error()
+			continue
+		case err != nil:
+			return nil, err
+		case len(record) != len(header):
+			row.Error = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			continue
+		}
+
+		for i, value := range record {
+			if fields[i] == "" {
+				continue
+			}
+			if err := setField(row, fields[i], value); err != nil {
+				row.Error = err.Error()
+				break
+			}
+		}
+	}
+}
+
+// decodeJSON reads objects either from a single array or, for NDJSON, one
+// after another.
+func decodeJSON(r io.Reader, mapping map[string]string, maxRows int, array bool) ([]*entities.ImportRow, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	if array {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return nil, errors.New("JSON import must be an array of objects")
+		}
+	}
+
+	var rows []*entities.ImportRow
+	for dec.More() {
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+
+		var object map[string]interface{}
+		if err := dec.Decode(&object); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, fmt.Errorf("row %d: %w", len(rows)+1, err)
+			}
+		}
+
+		row := &entities.ImportRow{Row: len(rows) + 1}
+		rows = append(rows, row)
+
+		if object == nil {
+			row.Error = "row is not an object"
+			continue
+		}
+
+		for key, value := range object {
+			field := fieldOf(key, mapping)
+			if field == "" {
+				continue
+			}
+			if err := setField(row, field, value); err != nil {
+				row.Error = err.Error()
+				break
+			}
+		}
+	}
+
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("reading end of array: %w", err)
+		}
+	}
+
+	return rows, nil
+}
+
+// setField stores a CSV string or decoded JSON value in the row. Empty
+// values leave the field unset.
+func setField(row *entities.ImportRow, field string, value interface{}) error {
+	if s, ok := value.(string); value == nil || (ok && strings.TrimSpace(s) == "") {
+		return nil
+	}
+
+	var err error
+	switch field {
+	case "title":
+		row.Task.Title, err = asString(value)
+	case "description":
+		row.Task.Description, err = asString(value)
+	case "priority":
+		row.Task.Priority, err = asString(value)
+	case "status":
+		row.StatusID, err = asStatus(value)
+	case "deadline":
+		row.Task.Deadline, err = asTime(value)
+	case "assignee_id":
+		row.Task.AssigneeID, err = asID(value)
+	case "project_id":
+		row.Task.ProjectID, err = asID(value)
+	case "labels":
+		row.Task.Labels, err = asLabels(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+
+	return nil
+}
+
+func asString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+
+	return "", errors.New("must be a string")
+}
+
+func asStatus(value interface{}) (int, error) {
+	s, err := asString(value)
+	if err != nil {
+		return 0, err
+	}
+
+	code := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "_")
+	for id, c := range statusCodes {
+		if c == code || strconv.Itoa(id) == code {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown status %q", s)
+}
+
+func asTime(value interface{}) (time.Time, error) {
+	s, err := asString(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, layout := range deadlineLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date", s)
+}
+
+func asID(value interface{}) (*int, error) {
+	s, err := asString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || id < 1 {
+		return nil, fmt.Errorf("%q is not a valid id", s)
+	}
+
+	return &id, nil
+}
+
+func asLabels(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return strings.Split(v, labelSeparator), nil
+	case []interface{}:
+		labels := make([]string, len(v))
+		for i, l := range v {
+			s, ok := l.(string)
+			if !ok {
+				return nil, errors.New("must be a list of strings")
+			}
+			labels[i] = s
+		}
+		return labels, nil
+	}
+
+	return nil, errors.New("must be a list of strings")
+}
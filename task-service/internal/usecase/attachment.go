@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/storage"
+)
+
+// sniffLen is how many bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, a *entities.Attachment) error
+	GetById(ctx context.Context, taskID, id int) (*entities.Attachment, error)
+	GetAllByTaskId(ctx context.Context, taskID int) ([]*entities.Attachment, error)
+	Delete(ctx context.Context, taskID, id int) error
+}
+
+type TaskGetter interface {
+	GetById(ctx context.Context, id int) (*entities.Task, error)
+}
+
+type AttachmentUsecaseConfig struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+type AttachmentUsecase struct {
+	repository AttachmentRepository
+	tasks      TaskGetter
+	projects   ProjectAccess
+	store      storage.BlobStore
+	logger     logger.ILogger
+	cfg        AttachmentUsecaseConfig
+}
+
+func NewAttachmentUsecase(r AttachmentRepository, t TaskGetter, p ProjectAccess, s storage.BlobStore, l logger.ILogger, cfg AttachmentUsecaseConfig) *AttachmentUsecase {
+	return &AttachmentUsecase{
+		repository: r,
+		tasks:      t,
+		projects:   p,
+		store:      s,
+		logger:     l,
+		cfg:        cfg,
+	}
+}
+
+func (uc *AttachmentUsecase) GetAll(ctx context.Context, userID, taskID int) ([]*entities.Attachment, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.repository.GetAllByTaskId(ctx, taskID)
+}
+
+// Upload streams r into the blob store and records the attachment. The
+// content type is sniffed from the data, the declared one is only trusted
+// when sniffing gives nothing more specific than plain text or binary.
+func (uc *AttachmentUsecase) Upload(ctx context.Context, userID, taskID int, fileName, declaredType string, r io.Reader) (*entities.Attachment, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	fileName = path.Base(fileName)
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return nil, app.NewAppError(app.ErrInvalidInput, "file name is required", nil)
+	}
+
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	contentType := detectContentType(head, declaredType)
+	if !uc.allowed(contentType) {
+		return nil, app.NewAppError(app.ErrUnsupported, fmt.Sprintf("content type %s is not allowed", contentType), nil)
+	}
+
+	key, err := newStorageKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := &limitedReader{r: br, remaining: uc.cfg.MaxSize}
+	size, err := uc.store.Put(ctx, key, limited)
+	if limited.exceeded {
+		uc.deleteBlob(ctx, key)
+		return nil, app.NewAppError(app.ErrTooLarge, fmt.Sprintf("attachments are limited to %d bytes", uc.cfg.MaxSize), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := entities.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+
+	if err := uc.repository.Create(ctx, &attachment); err != nil {
+		uc.deleteBlob(ctx, key)
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// Open returns the attachment and a reader for its contents which the
+// caller must close.
+func (uc *AttachmentUsecase) Open(ctx context.Context, userID, taskID, id int) (*entities.Attachment, io.ReadCloser, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := uc.repository.GetById(ctx, taskID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rc, err := uc.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, app.Wrap(err, app.ErrNotFound, fmt.Sprintf("contents of attachment %d are missing", id))
+		}
+		return nil, nil, err
+	}
+
+	return attachment, rc, nil
+}
+
+func (uc *AttachmentUsecase) Delete(ctx context.Context, userID, taskID, id int) error {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	attachment, err := uc.repository.GetById(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repository.Delete(ctx, taskID, id); err != nil {
+		return err
+	}
+
+	uc.deleteBlob(ctx, attachment.StorageKey)
+
+	return nil
+}
+
+func (uc *AttachmentUsecase) checkTask(ctx context.Context, userID, taskID int, min string) error {
+	task, err := uc.tasks.GetById(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return requireTaskAccess(ctx, uc.projects, task, userID, min)
+}
+
+func (uc *AttachmentUsecase) allowed(contentType string) bool {
+	if len(uc.cfg.AllowedTypes) == 0 {
+		return true
+	}
+
+	for _, t := range uc.cfg.AllowedTypes {
+		if t == contentType || (len(t) > 2 && t[len(t)-2:] == "/*" && path.Dir(contentType) == t[:len(t)-2]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteBlob removes a blob that is no longer referenced. Failures only
+// leave garbage behind, so they are logged rather than returned.
+func (uc *AttachmentUsecase) deleteBlob(ctx context.Context, key string) {
+	if err := uc.store.Delete(ctx, key); err != nil {
+		uc.logger.Error("Failed to delete attachment blob", "key", key, "error", err.Error())
+	}
+}
+
+func detectContentType(head []byte, declared string) string {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	declared, _, err := mime.ParseMediaType(declared)
+	if err == nil && (detected == "application/octet-stream" || detected == "text/plain") && declared != "" {
+		return declared
+	}
+
+	return detected
+}
+
+func newStorageKey(taskID int) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(buf)), nil
+}
+
+// limitedReader fails once more than remaining bytes have been read.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n, fmt.Errorf("upload exceeds size limit")
+	}
+
+	return n, err
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+// errBulkFailed rolls back an atomic bulk request after one of its
+// operations failed.
+var errBulkFailed = errors.New("bulk operation failed")
+
+// Bulk runs the operations in a single transaction. In atomic mode the
+// first failure rolls back everything and skips the remaining operations;
+// in best-effort mode only the failed operations are undone.
+func (uc *TaskUsecase) Bulk(ctx context.Context, userID int, req *entities.BulkRequest) (*entities.BulkResponse, error) {
+	if req.Mode == "" {
+		req.Mode = entities.BulkAtomic
+	}
+	if req.Mode != entities.BulkAtomic && req.Mode != entities.BulkBestEffort {
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("mode must be %q or %q", entities.BulkAtomic, entities.BulkBestEffort), nil)
+	}
+
+	if len(req.Operations) == 0 {
+		return nil, app.NewAppError(app.ErrInvalidInput, "no operations given", nil)
+	}
+	if len(req.Operations) > uc.cfg.MaxBulkOperations {
+		return nil, app.NewAppError(app.ErrTooLarge, fmt.Sprintf("at most %d operations are allowed per request", uc.cfg.MaxBulkOperations), nil)
+	}
+
+	results := make([]*entities.BulkResult, len(req.Operations))
+	failed := false
+
+	err := uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		for i := range req.Operations {
+			op := &req.Operations[i]
+			result := &entities.BulkResult{Index: i, Op: op.Op, Status: entities.BulkStatusOK}
+			results[i] = result
+
+			if failed && req.Mode == entities.BulkAtomic {
+				result.Status = entities.BulkStatusSkipped
+				continue
+			}
+
+			err := uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+				task, err := uc.bulkOne(ctx, userID, op)
+				result.Task = task
+				return err
+			})
+			if err != nil {
+				failed = true
+				result.Status, result.Task = entities.BulkStatusFailed, nil
+				result.Code, result.Error = uc.bulkError(err, i)
+			}
+		}
+
+		if failed && req.Mode == entities.BulkAtomic {
+			return errBulkFailed
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errBulkFailed) {
+		for _, result := range results {
+			if result.Status == entities.BulkStatusOK {
+				result.Status, result.Task = entities.BulkStatusRolledBack, nil
+			}
+		}
+		return &entities.BulkResponse{Committed: false, Results: results}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.BulkResponse{Committed: true, Results: results}, nil
+}
+
+func (uc *TaskUsecase) bulkOne(ctx context.Context, userID int, op *entities.BulkOperation) (*entities.Task, error) {
+	if op.Op != entities.BulkCreate && op.ID < 1 {
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("%s requires a task id", op.Op), nil)
+	}
+
+	switch op.Op {
+	case entities.BulkCreate:
+		if op.Task == nil || op.Task.Title == "" {
+			return nil, app.NewAppError(app.ErrInvalidInput, "create requires a task with a title", nil)
+		}
+		dto := *op.Task
+		dto.UserID = userID
+		return uc.Create(ctx, &dto)
+	case entities.BulkUpdate:
+		if len(op.Fields) == 0 {
+			return nil, app.NewAppError(app.ErrInvalidInput, "update requires fields", nil)
+		}
+		return uc.Patch(ctx, userID, op.ID, op.Version, &entities.TaskPatch{Fields: op.Fields}, "")
+	case entities.BulkTransition:
+		if op.StatusID == 0 {
+			return nil, app.NewAppError(app.ErrInvalidInput, "transition requires a status_id", nil)
+		}
+		fields := map[string]json.RawMessage{"status_id": json.RawMessage(fmt.Sprint(op.StatusID))}
+		return uc.Patch(ctx, userID, op.ID, op.Version, &entities.TaskPatch{Fields: fields}, "")
+	case entities.BulkAddLabel:
+		return uc.AddLabel(ctx, userID, op.ID, op.Version, op.Label)
+	case entities.BulkDelete:
+		return nil, uc.Delete(ctx, userID, op.ID, op.Version)
+	}
+
+	return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown operation %q", op.Op), nil)
+}
+
+// AddLabel adds a label to the task unless it already carries it. The
+// labels are written only if the task is still at the version they were
+// read from, so that a concurrent change of them is not lost; a non-zero
+// version must match as well.
+func (uc *TaskUsecase) AddLabel(ctx context.Context, userID, id, version int, label string) (*entities.Task, error) {
+	current, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, current, userID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = current.Version
+	}
+
+	labels, err := json.Marshal(append(append([]string{}, current.Labels...), label))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.Patch(ctx, userID, id, version, &entities.TaskPatch{Fields: map[string]json.RawMessage{"labels": labels}}, "")
+}
+
+// bulkError describes a failed operation or import row to the client. Unexpected errors
+// are logged and reported without their details.
+func (uc *TaskUsecase) bulkError(err error, index int) (code, message string) {
+	var appErr *app.AppError
+	if errors.As(err, &appErr) {
+		return string(appErr.Type), appErr.Message
+	}
+
+	uc.logger.Error("Bulk operation failed", "index", index, "error", err.Error())
+	return string(app.ErrInternal), "internal error"
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// labelRepository holds a single task and records the versions writes of
+// it are conditional on.
+type labelRepository struct {
+	stubTaskRepository
+	task     entities.Task
+	versions []int
+}
+
+func (r *labelRepository) GetById(ctx context.Context, id int) (*entities.Task, error) {
+	task := r.task
+	return &task, nil
+}
+
+func (r *labelRepository) UpdateColumns(ctx context.Context, t *entities.Task, columns []string, actorID int) error {
+	r.versions = append(r.versions, t.Version)
+	return nil
+}
+
+func (r *labelRepository) GetDescendants(ctx context.Context, id int) ([]*entities.Task, error) {
+	return nil, nil
+}
+
+func TestAddLabelIsConditional(t *testing.T) {
+	repo := &labelRepository{task: entities.Task{ID: 1, UserId: 7, StatusID: entities.StatusNew, Priority: entities.PriorityNormal, Labels: []string{"bug"}, Version: 4}}
+	uc := NewTaskUsecase(repo, nil, nil, nopLogger{}, TaskUsecaseConfig{})
+
+	if _, err := uc.AddLabel(context.Background(), 7, 1, 0, "ui"); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+
+	if len(repo.versions) != 1 || repo.versions[0] != 4 {
+		t.Fatalf("labels written at versions %v, want the version they were read at", repo.versions)
+	}
+}
+
+func TestAddLabelChecksAccess(t *testing.T) {
+	repo := &labelRepository{task: entities.Task{ID: 1, UserId: 7, StatusID: entities.StatusNew, Version: 4}}
+	uc := NewTaskUsecase(repo, nil, nil, nopLogger{}, TaskUsecaseConfig{})
+
+	if _, err := uc.AddLabel(context.Background(), 8, 1, 0, "ui"); err == nil {
+		t.Fatal("AddLabel succeeded for a user who cannot see the task")
+	}
+	if len(repo.versions) != 0 {
+		t.Fatalf("labels written %d times, want none", len(repo.versions))
+	}
+}
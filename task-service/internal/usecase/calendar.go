@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// calendarTokenBytes is the entropy of a feed token, which is all that
+// protects the feed.
+const calendarTokenBytes = 32
+
+type CalendarRepository interface {
+	GetToken(ctx context.Context, userID int) (*entities.CalendarToken, error)
+	SaveToken(ctx context.Context, t *entities.CalendarToken) error
+	GetUserID(ctx context.Context, token string) (int, error)
+}
+
+type TaskExporter interface {
+	Export(ctx context.Context, f entities.TaskFilter, fn func(t *entities.Task) error) error
+}
+
+type CalendarUsecase struct {
+	repository CalendarRepository
+	tasks      TaskExporter
+	logger     logger.ILogger
+}
+
+func NewCalendarUsecase(r CalendarRepository, t TaskExporter, l logger.ILogger) *CalendarUsecase {
+	return &CalendarUsecase{
+		repository: r,
+		tasks:      t,
+		logger:     l,
+	}
+}
+
+// GetToken returns the user's feed token, issuing one on first use.
+func (uc *CalendarUsecase) GetToken(ctx context.Context, userID int) (*entities.CalendarToken, error) {
+	t, err := uc.repository.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t != nil {
+		return t, nil
+	}
+
+	return uc.RegenerateToken(ctx, userID)
+}
+
+// RegenerateToken replaces the user's feed token, so that the previous
+// feed URL stops working.
+func (uc *CalendarUsecase) RegenerateToken(ctx context.Context, userID int) (*entities.CalendarToken, error) {
+	buf := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	t := &entities.CalendarToken{UserID: userID, Token: hex.EncodeToString(buf)}
+	if err := uc.repository.SaveToken(ctx, t); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Calendar token issued", "user_id", userID)
+	return t, nil
+}
+
+// Feed calls fn for every task visible to the owner of token.
+func (uc *CalendarUsecase) Feed(ctx context.Context, token string, fn func(t *entities.Task) error) error {
+	userID, err := uc.repository.GetUserID(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return uc.tasks.Export(ctx, entities.TaskFilter{UserID: userID}, fn)
+}
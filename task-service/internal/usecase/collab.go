@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+type CollabUsecaseConfig struct {
+	// BufferSize is how many messages a client may fall behind before it
+	// is disconnected.
+	BufferSize int
+	// MaxTopics caps the subscriptions of a single connection.
+	MaxTopics int
+}
+
+// CollabUsecase keeps track of who is looking at which task or project and
+// relays presence and typing indicators between them. Presence is local to
+// the replica; task updates are not, as they come from the event stream.
+type CollabUsecase struct {
+	tasks    TaskGetter
+	projects ProjectAccess
+	logger   logger.ILogger
+	cfg      CollabUsecaseConfig
+
+	mu      sync.Mutex
+	topics  map[string]map[*entities.CollabClient]struct{}
+	clients map[*entities.CollabClient]struct{}
+	closed  bool
+	// connected counts clients between Connect and Disconnect.
+	connected sync.WaitGroup
+}
+
+func NewCollabUsecase(t TaskGetter, p ProjectAccess, l logger.ILogger, cfg CollabUsecaseConfig) *CollabUsecase {
+	return &CollabUsecase{
+		tasks:    t,
+		projects: p,
+		logger:   l,
+		cfg:      cfg,
+		topics:   make(map[string]map[*entities.CollabClient]struct{}),
+		clients:  make(map[*entities.CollabClient]struct{}),
+	}
+}
+
+// Connect registers a client, which must be followed by exactly one
+// Disconnect. Its Send channel is closed right away when the service is
+// already shutting down.
+func (uc *CollabUsecase) Connect(userID int) *entities.CollabClient {
+	c := &entities.CollabClient{
+		UserID: userID,
+		Send:   make(chan *entities.CollabMessage, uc.cfg.BufferSize),
+		Topics: make(map[string]bool),
+	}
+
+	uc.connected.Add(1)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.closed {
+		c.CloseReason = entities.CollabClosedShutdown
+		close(c.Send)
+		return c
+	}
+	uc.clients[c] = struct{}{}
+
+	return c
+}
+
+// Disconnect leaves every topic of the client.
+func (uc *CollabUsecase) Disconnect(c *entities.CollabClient) {
+	defer uc.connected.Done()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.drop(c, "")
+}
+
+// Subscribe joins the topic once the user is found to have access to it
+// and returns who is present on it.
+func (uc *CollabUsecase) Subscribe(ctx context.Context, c *entities.CollabClient, topic string) ([]int, error) {
+	if err := uc.authorize(ctx, c.UserID, topic); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.clients[c]; !ok {
+		return nil, app.NewAppError(app.ErrConflict, "connection is closing", nil)
+	}
+	if c.Topics[topic] {
+		return uc.present(topic), nil
+	}
+	if len(c.Topics) >= uc.cfg.MaxTopics {
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("at most %d topics per connection", uc.cfg.MaxTopics), nil)
+	}
+
+	announce := !slices.Contains(uc.present(topic), c.UserID)
+
+	if uc.topics[topic] == nil {
+		uc.topics[topic] = make(map[*entities.CollabClient]struct{})
+	}
+	uc.topics[topic][c] = struct{}{}
+	c.Topics[topic] = true
+
+	if announce {
+		uc.broadcast(topic, c, &entities.CollabMessage{Type: entities.CollabJoined, Topic: topic, UserID: c.UserID})
+	}
+
+	return uc.present(topic), nil
+}
+
+func (uc *CollabUsecase) Unsubscribe(c *entities.CollabClient, topic string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.leave(c, topic)
+}
+
+// Typing tells the other clients on the topic that the user started or
+// stopped typing.
+func (uc *CollabUsecase) Typing(c *entities.CollabClient, topic string, typing bool) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if !c.Topics[topic] {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("not subscribed to %s", topic), nil)
+	}
+
+	uc.broadcast(topic, c, &entities.CollabMessage{Type: entities.CollabTyping, Topic: topic, UserID: c.UserID, Typing: &typing})
+	return nil
+}
+
+// Topics returns the subscribed topics an event belongs to.
+func (uc *CollabUsecase) Topics(c *entities.CollabClient, ev *entities.StreamEvent) []string {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	var topics []string
+	if topic := fmt.Sprintf("task:%d", ev.TaskID); c.Topics[topic] {
+		topics = append(topics, topic)
+	}
+	if ev.Task != nil && ev.Task.ProjectID != nil {
+		if topic := fmt.Sprintf("project:%d", *ev.Task.ProjectID); c.Topics[topic] {
+			topics = append(topics, topic)
+		}
+	}
+
+	return topics
+}
+
+// Run waits for ctx to be cancelled and then disconnects every client.
+func (uc *CollabUsecase) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.closed = true
+	for c := range uc.clients {
+		uc.drop(c, entities.CollabClosedShutdown)
+	}
+	uc.logger.Info("Collaboration hub stopped")
+}
+
+// Wait blocks until every client has disconnected, which they do once Run
+// has told them to, or until ctx is done.
+func (uc *CollabUsecase) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		uc.connected.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (uc *CollabUsecase) authorize(ctx context.Context, userID int, topic string) error {
+	kind, rawID, _ := strings.Cut(topic, ":")
+	id, err := strconv.Atoi(rawID)
+	if err != nil || id < 1 {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("invalid topic %q", topic), nil)
+	}
+
+	switch kind {
+	case "task":
+		t, err := uc.tasks.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		return requireTaskAccess(ctx, uc.projects, t, userID, entities.RoleViewer)
+	case "project":
+		return requireRole(ctx, uc.projects, id, userID, entities.RoleViewer)
+	}
+
+	return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("invalid topic %q", topic), nil)
+}
+
+// present returns the users on the topic. The caller holds mu.
+func (uc *CollabUsecase) present(topic string) []int {
+	users := []int{}
+	for c := range uc.topics[topic] {
+		if !slices.Contains(users, c.UserID) {
+			users = append(users, c.UserID)
+		}
+	}
+	slices.Sort(users)
+
+	return users
+}
+
+// leave removes the client from the topic and announces the user left
+// when this was their last connection on it. The caller holds mu.
+func (uc *CollabUsecase) leave(c *entities.CollabClient, topic string) {
+	if !c.Topics[topic] {
+		return
+	}
+
+	delete(c.Topics, topic)
+	delete(uc.topics[topic], c)
+	if len(uc.topics[topic]) == 0 {
+		delete(uc.topics, topic)
+	}
+
+	if !slices.Contains(uc.present(topic), c.UserID) {
+		uc.broadcast(topic, c, &entities.CollabMessage{Type: entities.CollabLeft, Topic: topic, UserID: c.UserID})
+	}
+}
+
+// drop unregisters the client, closing its channel with the given reason
+// unless the reason is empty, as on a disconnect. The caller holds mu.
+func (uc *CollabUsecase) drop(c *entities.CollabClient, reason string) {
+	if _, ok := uc.clients[c]; !ok {
+		return
+	}
+
+	delete(uc.clients, c)
+	for topic := range c.Topics {
+		uc.leave(c, topic)
+	}
+
+	c.CloseReason = reason
+	close(c.Send)
+}
+
+// broadcast queues msg for every client on the topic but the sender.
+// Clients that cannot keep up are dropped. The caller holds mu.
+func (uc *CollabUsecase) broadcast(topic string, sender *entities.CollabClient, msg *entities.CollabMessage) {
+	var slow []*entities.CollabClient
+	for c := range uc.topics[topic] {
+		if c == sender {
+			continue
+		}
+
+		select {
+		case c.Send <- msg:
+		default:
+			slow = append(slow, c)
+		}
+	}
+
+	for _, c := range slow {
+		uc.logger.Warn("Dropping slow collaboration client", "user_id", c.UserID, "topic", topic)
+		uc.drop(c, entities.CollabClosedSlow)
+	}
+}
@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+// AddDependency marks taskID as blocked by blockedByID. The user needs to
+// be able to edit the blocked task and to see the blocking one.
+func (uc *TaskUsecase) AddDependency(ctx context.Context, userID, taskID, blockedByID int) error {
+	if taskID == blockedByID {
+		return app.NewAppError(app.ErrInvalidInput, "task cannot block itself", nil)
+	}
+
+	task, err := uc.repository.GetById(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	blocker, err := uc.repository.GetById(ctx, blockedByID)
+	if err == nil {
+		err = requireTaskAccess(ctx, uc.projects, blocker, userID, entities.RoleViewer)
+	}
+	var appErr *app.AppError
+	if errors.As(err, &appErr) && appErr.Type == app.ErrNotFound {
+		return app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("blocking task %d does not exist", blockedByID))
+	}
+	if err != nil {
+		return err
+	}
+
+	return uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		if err := uc.repository.LockDependencies(ctx); err != nil {
+			return err
+		}
+
+		edges, err := uc.repository.GetDependencyComponent(ctx, blockedByID)
+		if err != nil {
+			return err
+		}
+
+		if dependsOn(edges, blockedByID, taskID) {
+			return app.NewAppError(app.ErrConflict, fmt.Sprintf("task %d already depends on %d, link would create a cycle", blockedByID, taskID), nil)
+		}
+
+		return uc.repository.AddDependency(ctx, entities.Dependency{TaskID: taskID, BlockedByID: blockedByID})
+	})
+}
+
+func (uc *TaskUsecase) RemoveDependency(ctx context.Context, userID, taskID, blockedByID int) error {
+	task, err := uc.repository.GetById(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	return uc.repository.RemoveDependency(ctx, entities.Dependency{TaskID: taskID, BlockedByID: blockedByID})
+}
+
+// GetGraph returns the dependency DAG the task belongs to together with a
+// topological ordering and its critical path. Tasks of the graph the user
+// may not view are listed by id only and count as having no deadline or
+// estimate.
+func (uc *TaskUsecase) GetGraph(ctx context.Context, userID, id int) (*entities.TaskGraph, error) {
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	edges, err := uc.repository.GetDependencyComponent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*entities.Task{task}
+	if len(edges) > 0 {
+		ids := map[int]struct{}{}
+		for _, e := range edges {
+			ids[e.TaskID] = struct{}{}
+			ids[e.BlockedByID] = struct{}{}
+		}
+
+		list := make([]int, 0, len(ids))
+		for id := range ids {
+			list = append(list, id)
+		}
+
+		tasks, err = uc.repository.GetByIds(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks, err = uc.redactTasks(ctx, userID, tasks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	order, err := topoSort(tasks, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	if edges == nil {
+		edges = []entities.Dependency{}
+	}
+
+	return &entities.TaskGraph{
+		Tasks:        tasks,
+		Edges:        edges,
+		Order:        order,
+		CriticalPath: criticalPath(tasks, edges, order, time.Now()),
+	}, nil
+}
+
+// redactTasks replaces the tasks the user may not view by their id.
+func (uc *TaskUsecase) redactTasks(ctx context.Context, userID int, tasks []*entities.Task) ([]*entities.Task, error) {
+	result := make([]*entities.Task, 0, len(tasks))
+	for _, t := range tasks {
+		err := requireTaskAccess(ctx, uc.projects, t, userID, entities.RoleViewer)
+		if err != nil {
+			var appErr *app.AppError
+			if !errors.As(err, &appErr) || appErr.Type != app.ErrNotFound && appErr.Type != app.ErrUnauthorized {
+				return nil, err
+			}
+			t = &entities.Task{ID: t.ID}
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+// checkBlockers refuses to start or complete a task while any of the tasks
+// blocking it are still open.
+func (uc *TaskUsecase) checkBlockers(ctx context.Context, id int) error {
+	open, err := uc.repository.CountOpenBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if open > 0 {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("task is blocked by %d open tasks", open), nil)
+	}
+
+	return nil
+}
+
+// dependsOn reports whether from is transitively blocked by to.
+func dependsOn(edges []entities.Dependency, from, to int) bool {
+	blockers := map[int][]int{}
+	for _, e := range edges {
+		blockers[e.TaskID] = append(blockers[e.TaskID], e.BlockedByID)
+	}
+
+	visited := map[int]bool{}
+	stack := []int{from}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == to {
+			return true
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		stack = append(stack, blockers[cur]...)
+	}
+
+	return false
+}
+
+// topoSort orders tasks so that blockers come first, preferring earlier
+// deadlines among tasks that are ready at the same time.
+func topoSort(tasks []*entities.Task, edges []entities.Dependency) ([]int, error) {
+	byID := map[int]*entities.Task{}
+	inDegree := map[int]int{}
+	for _, t := range tasks {
+		byID[t.ID] = t
+		inDegree[t.ID] = 0
+	}
+
+	dependents := map[int][]int{}
+	for _, e := range edges {
+		dependents[e.BlockedByID] = append(dependents[e.BlockedByID], e.TaskID)
+		inDegree[e.TaskID]++
+	}
+
+	var ready []int
+	for id, d := range inDegree {
+		if d == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]int, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			di, dj := effectiveDeadline(byID[ready[i]]), effectiveDeadline(byID[ready[j]])
+			if !di.Equal(dj) {
+				return di.Before(dj)
+			}
+			return ready[i] < ready[j]
+		})
+
+		cur := ready[0]
+		ready = ready[1:]
+		order = append(order, cur)
+
+		for _, next := range dependents[cur] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(order) != len(inDegree) {
+		return nil, app.NewAppError(app.ErrConflict, "dependency graph contains a cycle", nil)
+	}
+
+	return order, nil
+}
+
+// pathNode is a task in the critical path computation.
+type pathNode struct {
+	// finish is the earliest the task can be done, latest the latest it
+	// may be done for its own and its dependents' deadlines to hold, zero
+	// when none of them has one.
+	finish time.Time
+	latest time.Time
+	hops   int
+	// driver is the blocker that finishes last, and so decides when the
+	// task can start.
+	driver int
+}
+
+// slack is how long the task may slip without missing a deadline.
+func (n *pathNode) slack() (time.Duration, bool) {
+	if n.latest.IsZero() {
+		return 0, false
+	}
+
+	return n.latest.Sub(n.finish), true
+}
+
+// criticalPath returns the chain of dependencies with the least slack,
+// given the deadlines and the estimated hours of the open tasks; tasks
+// without an estimate take no time. Without any deadline, or between
+// chains with equal slack, the one finishing last wins, then the longest.
+func criticalPath(tasks []*entities.Task, edges []entities.Dependency, order []int, now time.Time) []int {
+	byID := map[int]*entities.Task{}
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	blockers := map[int][]int{}
+	dependents := map[int][]int{}
+	for _, e := range edges {
+		blockers[e.TaskID] = append(blockers[e.TaskID], e.BlockedByID)
+		dependents[e.BlockedByID] = append(dependents[e.BlockedByID], e.TaskID)
+	}
+
+	nodes := make(map[int]*pathNode, len(order))
+	for _, id := range order {
+		n := &pathNode{finish: now, hops: 1}
+		for _, b := range blockers[id] {
+			if n.driver == 0 || finishesLater(nodes[b], nodes[n.driver]) {
+				n.driver = b
+			}
+		}
+		if n.driver != 0 {
+			driver := nodes[n.driver]
+			n.hops = driver.hops + 1
+			if driver.finish.After(n.finish) {
+				n.finish = driver.finish
+			}
+		}
+		n.finish = n.finish.Add(remainingWork(byID[id]))
+		nodes[id] = n
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		n := nodes[order[i]]
+		if t := byID[order[i]]; t != nil {
+			n.latest = t.Deadline
+		}
+		for _, d := range dependents[order[i]] {
+			dependent := nodes[d]
+			if dependent.latest.IsZero() {
+				continue
+			}
+			if start := dependent.latest.Add(-remainingWork(byID[d])); n.latest.IsZero() || start.Before(n.latest) {
+				n.latest = start
+			}
+		}
+	}
+
+	end := 0
+	for _, id := range order {
+		if end == 0 || moreCritical(nodes[id], nodes[end]) {
+			end = id
+		}
+	}
+
+	var path []int
+	for id := end; id != 0; id = nodes[id].driver {
+		path = append([]int{id}, path...)
+	}
+
+	return path
+}
+
+func moreCritical(a, b *pathNode) bool {
+	slackA, boundA := a.slack()
+	slackB, boundB := b.slack()
+	if boundA != boundB {
+		return boundA
+	}
+	if boundA && slackA != slackB {
+		return slackA < slackB
+	}
+
+	return finishesLater(a, b)
+}
+
+func finishesLater(a, b *pathNode) bool {
+	if !a.finish.Equal(b.finish) {
+		return a.finish.After(b.finish)
+	}
+
+	return a.hops > b.hops
+}
+
+// remainingWork is the estimated time left on the task.
+func remainingWork(t *entities.Task) time.Duration {
+	if t == nil || t.StatusID == entities.StatusCompleted || t.EstimateHours == nil {
+		return 0
+	}
+
+	return time.Duration(*t.EstimateHours * float64(time.Hour))
+}
+
+// effectiveDeadline sorts tasks without a deadline after all others.
+func effectiveDeadline(t *entities.Task) time.Time {
+	if t == nil || t.Deadline.IsZero() {
+		return time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return t.Deadline
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+func TestCriticalPath(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hour := 1.0
+
+	// 1 <- 2 <- 3 is the longer chain, 4 <- 5 the one with a deadline.
+	tasks := []*entities.Task{
+		{ID: 1, EstimateHours: &hour},
+		{ID: 2, EstimateHours: &hour},
+		{ID: 3, EstimateHours: &hour},
+		{ID: 4, EstimateHours: &hour},
+		{ID: 5, EstimateHours: &hour},
+	}
+	edges := []entities.Dependency{
+		{TaskID: 2, BlockedByID: 1},
+		{TaskID: 3, BlockedByID: 2},
+		{TaskID: 5, BlockedByID: 4},
+	}
+
+	order, err := topoSort(tasks, edges)
+	if err != nil {
+		t.Fatalf("topoSort: %v", err)
+	}
+
+	if got := criticalPath(tasks, edges, order, now); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Fatalf("without deadlines the path is %v, want the chain finishing last", got)
+	}
+
+	tasks[4].Deadline = now.Add(2 * time.Hour)
+	if got := criticalPath(tasks, edges, order, now); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Fatalf("path is %v, want the chain without slack", got)
+	}
+}
+
+func TestRedactTasks(t *testing.T) {
+	uc := NewTaskUsecase(&stubTaskRepository{}, nil, nil, nopLogger{}, TaskUsecaseConfig{})
+
+	tasks := []*entities.Task{
+		{ID: 1, UserId: 7, Title: "Mine"},
+		{ID: 2, UserId: 8, Title: "Someone else's", Description: "private"},
+	}
+	got, err := uc.redactTasks(context.Background(), 7, tasks)
+	if err != nil {
+		t.Fatalf("redactTasks: %v", err)
+	}
+
+	if got[0].Title != "Mine" {
+		t.Fatalf("own task = %+v, want it unchanged", got[0])
+	}
+	if got[1].ID != 2 || got[1].Title != "" || got[1].Description != "" {
+		t.Fatalf("other task = %+v, want only its id", got[1])
+	}
+}
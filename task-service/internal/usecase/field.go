@@ -0,0 +1,502 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+const (
+	maxProjectFields     = 50
+	maxFieldOptions      = 100
+	maxFieldOptionLength = 100
+	maxFieldTextLength   = 1000
+)
+
+var fieldTypes = map[string]bool{
+	entities.FieldText:        true,
+	entities.FieldNumber:      true,
+	entities.FieldDate:        true,
+	entities.FieldSelect:      true,
+	entities.FieldMultiSelect: true,
+	entities.FieldUser:        true,
+}
+
+func (uc *ProjectUsecase) GetFields(ctx context.Context, userID, projectID int) ([]*entities.ProjectField, error) {
+	if err := requireRole(ctx, uc.repository, projectID, userID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.repository.GetFields(ctx, projectID)
+}
+
+func (uc *ProjectUsecase) CreateField(ctx context.Context, userID int, f *entities.ProjectField) error {
+	if !entities.ValidFieldName(f.Name) {
+		return app.NewAppError(app.ErrInvalidInput, "field name must be 1 to 50 lower case letters, digits or underscores, starting with a letter", nil)
+	}
+	if !fieldTypes[f.Type] {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown field type %q", f.Type), nil)
+	}
+
+	options, err := checkFieldOptions(f.Type, f.Options)
+	if err != nil {
+		return err
+	}
+	f.Options = options
+
+	if err := requireRole(ctx, uc.repository, f.ProjectID, userID, entities.RoleOwner); err != nil {
+		return err
+	}
+
+	fields, err := uc.repository.GetFields(ctx, f.ProjectID)
+	if err != nil {
+		return err
+	}
+	if len(fields) >= maxProjectFields {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("a project can have at most %d custom fields", maxProjectFields), nil)
+	}
+
+	return uc.repository.CreateField(ctx, f)
+}
+
+// UpdateField replaces the options of a select or multi_select field.
+// Options still set on tasks cannot be removed.
+func (uc *ProjectUsecase) UpdateField(ctx context.Context, userID int, f *entities.ProjectField) error {
+	if err := requireRole(ctx, uc.repository, f.ProjectID, userID, entities.RoleOwner); err != nil {
+		return err
+	}
+
+	current, err := uc.repository.GetField(ctx, f.ProjectID, f.ID)
+	if err != nil {
+		return err
+	}
+
+	if f.Name != "" && f.Name != current.Name {
+		return app.NewAppError(app.ErrInvalidInput, "field name cannot be changed", nil)
+	}
+	if f.Type != "" && f.Type != current.Type {
+		return app.NewAppError(app.ErrInvalidInput, "field type cannot be changed", nil)
+	}
+
+	options, err := checkFieldOptions(current.Type, f.Options)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(options))
+	for _, o := range options {
+		kept[o] = true
+	}
+	for _, o := range current.Options {
+		if kept[o] {
+			continue
+		}
+		n, err := uc.repository.CountOptionUse(ctx, f.ProjectID, current.Name, o)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return app.NewAppError(app.ErrConflict, fmt.Sprintf("option %q is used by %d tasks", o, n), nil)
+		}
+	}
+
+	*f = *current
+	f.Options = options
+
+	return uc.repository.UpdateField(ctx, f)
+}
+
+// DeleteField removes the field along with its values on the project's
+// tasks.
+func (uc *ProjectUsecase) DeleteField(ctx context.Context, userID, projectID, id int) error {
+	if err := requireRole(ctx, uc.repository, projectID, userID, entities.RoleOwner); err != nil {
+		return err
+	}
+
+	field, err := uc.repository.GetField(ctx, projectID, id)
+	if err != nil {
+		return err
+	}
+
+	return uc.repository.DeleteField(ctx, field)
+}
+
+// checkFieldOptions validates the options of a field of the given type
+// and returns them trimmed. Only select fields take options.
+func checkFieldOptions(fieldType string, options []string) ([]string, error) {
+	if fieldType != entities.FieldSelect && fieldType != entities.FieldMultiSelect {
+		if len(options) > 0 {
+			return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("%s fields take no options", fieldType), nil)
+		}
+		return []string{}, nil
+	}
+
+	if len(options) == 0 || len(options) > maxFieldOptions {
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("%s fields need 1 to %d options", fieldType, maxFieldOptions), nil)
+	}
+
+	seen := make(map[string]bool, len(options))
+	result := make([]string, 0, len(options))
+	for _, o := range options {
+		o = strings.TrimSpace(o)
+		if o == "" || len(o) > maxFieldOptionLength {
+			return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("options must be 1 to %d characters long", maxFieldOptionLength), nil)
+		}
+		if seen[o] {
+			return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("duplicate option %q", o), nil)
+		}
+		seen[o] = true
+		result = append(result, o)
+	}
+
+	return result, nil
+}
+
+// fieldsByName returns the project's custom fields by name.
+func (uc *TaskUsecase) fieldsByName(ctx context.Context, projectID int) (map[string]*entities.ProjectField, error) {
+	fields, err := uc.projects.GetFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*entities.ProjectField, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	return byName, nil
+}
+
+// checkCustomFields validates the task's custom field values against the
+// fields of its project and brings them to their stored form. Null values
+// and empty multi_select lists unset the field. Values of fields the
+// project does not define are rejected, except that values carried over
+// from current are dropped: a task moved to another project, or out of
+// one, keeps only the fields both define. User fields are checked only
+// when changed.
+func (uc *TaskUsecase) checkCustomFields(ctx context.Context, t, current *entities.Task) error {
+	if len(t.CustomFields) == 0 {
+		t.CustomFields = entities.CustomFields{}
+		return nil
+	}
+
+	fields := map[string]*entities.ProjectField{}
+	if t.ProjectID != nil {
+		var err error
+		if fields, err = uc.fieldsByName(ctx, *t.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	var previous entities.CustomFields
+	if current != nil {
+		previous = current.CustomFields
+	}
+
+	values := entities.CustomFields{}
+	for name, raw := range t.CustomFields {
+		if raw == nil {
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			if old, kept := previous[name]; kept && sameFieldValue(old, raw) {
+				continue
+			}
+			if t.ProjectID == nil {
+				return app.NewAppError(app.ErrInvalidInput, "only project tasks have custom fields", nil)
+			}
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown custom field %q", name), nil)
+		}
+
+		value, err := fieldValue(field, raw)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			continue
+		}
+
+		if field.Type == entities.FieldUser {
+			if old, ok := previous[name]; !ok || !sameFieldValue(old, value) {
+				if err := uc.checkAssignee(ctx, value.(int)); err != nil {
+					return err
+				}
+			}
+		}
+
+		values[name] = value
+	}
+	t.CustomFields = values
+
+	return nil
+}
+
+// fieldValue converts a decoded JSON value of the field to its stored
+// form; nil means the field is unset.
+func fieldValue(f *entities.ProjectField, raw interface{}) (interface{}, error) {
+	invalid := func(want string) error {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("custom field %q must be %s", f.Name, want), nil)
+	}
+
+	switch f.Type {
+	case entities.FieldText:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, invalid("a string")
+		}
+		if len(s) > maxFieldTextLength {
+			return nil, invalid(fmt.Sprintf("at most %d characters long", maxFieldTextLength))
+		}
+		return s, nil
+	case entities.FieldNumber:
+		n, ok := raw.(float64)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, invalid("a number")
+		}
+		return n, nil
+	case entities.FieldDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, invalid("a date (YYYY-MM-DD)")
+		}
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, invalid("a date (YYYY-MM-DD)")
+		}
+		return s, nil
+	case entities.FieldSelect:
+		s, ok := raw.(string)
+		if !ok || !hasOption(f, s) {
+			return nil, invalid("one of " + strings.Join(f.Options, ", "))
+		}
+		return s, nil
+	case entities.FieldMultiSelect:
+		list, ok := raw.([]interface{})
+		if !ok {
+			if l, typed := raw.([]string); typed {
+				list = make([]interface{}, len(l))
+				for i, s := range l {
+					list[i] = s
+				}
+			} else {
+				return nil, invalid("a list of options")
+			}
+		}
+		seen := make(map[string]bool, len(list))
+		result := make([]string, 0, len(list))
+		for _, v := range list {
+			s, ok := v.(string)
+			if !ok || !hasOption(f, s) {
+				return nil, invalid("a list of " + strings.Join(f.Options, ", "))
+			}
+			if !seen[s] {
+				seen[s] = true
+				result = append(result, s)
+			}
+		}
+		if len(result) == 0 {
+			return nil, nil
+		}
+		return result, nil
+	case entities.FieldUser:
+		var id int
+		switch v := raw.(type) {
+		case float64:
+			id = int(v)
+			if float64(id) != v {
+				return nil, invalid("a user ID")
+			}
+		case int:
+			id = v
+		default:
+			return nil, invalid("a user ID")
+		}
+		if id <= 0 {
+			return nil, invalid("a user ID")
+		}
+		return id, nil
+	}
+
+	return nil, app.NewAppError(app.ErrInternal, fmt.Sprintf("custom field %q has unknown type %q", f.Name, f.Type), nil)
+}
+
+func hasOption(f *entities.ProjectField, option string) bool {
+	for _, o := range f.Options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameFieldValue compares values as they appear in JSON, so that a
+// stored value matches the same value decoded from a request.
+func sameFieldValue(a, b interface{}) bool {
+	return fmt.Sprint(normalizeFieldValue(a)) == fmt.Sprint(normalizeFieldValue(b))
+}
+
+func normalizeFieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	}
+
+	return v
+}
+
+// resolveFieldFilter turns the raw cf.<name> filters into the typed
+// document the task custom fields must contain. Filtering by custom
+// fields needs a project.
+func (uc *TaskUsecase) resolveFieldFilter(ctx context.Context, f *entities.TaskFilter) error {
+	if len(f.CustomFields) == 0 {
+		return nil
+	}
+
+	if f.ProjectID == nil {
+		return app.NewAppError(app.ErrInvalidInput, "custom field filters need a project_id", nil)
+	}
+
+	fields, err := uc.fieldsByName(ctx, *f.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	match := entities.CustomFields{}
+	for name, raw := range f.CustomFields {
+		field, ok := fields[name]
+		if !ok {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown custom field %q", name), nil)
+		}
+
+		var value interface{}
+		switch field.Type {
+		case entities.FieldNumber:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("custom field %q must be a number", name), err)
+			}
+			value = n
+		case entities.FieldUser:
+			if raw == "me" {
+				value = f.UserID
+				break
+			}
+			id, err := strconv.Atoi(raw)
+			if err != nil {
+				return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("custom field %q must be a user ID or me", name), err)
+			}
+			value = id
+		case entities.FieldMultiSelect:
+			value = []string{raw}
+		default:
+			value = raw
+		}
+
+		if field.Type != entities.FieldMultiSelect {
+			if _, err := fieldValue(field, value); err != nil {
+				return err
+			}
+		} else if !hasOption(field, raw) {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("custom field %q has no option %q", name, raw), nil)
+		}
+		match[name] = value
+	}
+	f.FieldMatch = match
+
+	return nil
+}
+
+// sortByField orders tasks by the custom field, tasks without a value
+// last in either direction. Ties keep the list order.
+func (uc *TaskUsecase) sortByField(ctx context.Context, tasks []*entities.Task, projectID *int, name string, desc bool) error {
+	if projectID == nil {
+		return app.NewAppError(app.ErrInvalidInput, "sorting by a custom field needs a project_id", nil)
+	}
+
+	fields, err := uc.fieldsByName(ctx, *projectID)
+	if err != nil {
+		return err
+	}
+
+	field, ok := fields[name]
+	if !ok {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown custom field %q", name), nil)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, aok := tasks[i].CustomFields[name]
+		b, bok := tasks[j].CustomFields[name]
+		if !aok || !bok {
+			return aok && !bok
+		}
+
+		c := compareFieldValues(field, a, b)
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	return nil
+}
+
+// compareFieldValues orders numbers and user IDs numerically, select
+// fields by the order of their options and the rest as strings.
+func compareFieldValues(f *entities.ProjectField, a, b interface{}) int {
+	switch f.Type {
+	case entities.FieldNumber, entities.FieldUser:
+		x, _ := normalizeFieldValue(a).(float64)
+		y, _ := normalizeFieldValue(b).(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case entities.FieldSelect:
+		return optionIndex(f, fmt.Sprint(a)) - optionIndex(f, fmt.Sprint(b))
+	case entities.FieldMultiSelect:
+		return firstOption(f, a) - firstOption(f, b)
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func optionIndex(f *entities.ProjectField, option string) int {
+	for i, o := range f.Options {
+		if o == option {
+			return i
+		}
+	}
+
+	return len(f.Options)
+}
+
+// firstOption returns the index of the earliest option in the list.
+func firstOption(f *entities.ProjectField, v interface{}) int {
+	first := len(f.Options)
+	list, _ := normalizeFieldValue(v).([]interface{})
+	for _, s := range list {
+		if i := optionIndex(f, fmt.Sprint(s)); i < first {
+			first = i
+		}
+	}
+
+	return first
+}
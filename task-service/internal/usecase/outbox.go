@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/publisher"
+)
+
+type OutboxRepository interface {
+	ProcessOutbox(ctx context.Context, limit int, publish func(ctx context.Context, m *entities.OutboxMessage) error,
+		retryAt func(attempts int) time.Time) (int, error)
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type OutboxUsecaseConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// Failed publishes back off exponentially from MinBackoff up to
+	// MaxBackoff and are retried for as long as it takes.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Retention is how long published events stay in the outbox.
+	Retention time.Duration
+}
+
+// OutboxUsecase relays the events written to the outbox, in the same
+// transaction as the task changes, to the event publisher. Delivery is at
+// least once: consumers deduplicate by the event id.
+type OutboxUsecase struct {
+	repository OutboxRepository
+	publisher  publisher.EventPublisher
+	logger     logger.ILogger
+	cfg        OutboxUsecaseConfig
+}
+
+func NewOutboxUsecase(r OutboxRepository, p publisher.EventPublisher, l logger.ILogger, cfg OutboxUsecaseConfig) *OutboxUsecase {
+	return &OutboxUsecase{
+		repository: r,
+		publisher:  p,
+		logger:     l,
+		cfg:        cfg,
+	}
+}
+
+// Run relays pending events every Interval until ctx is cancelled.
+func (uc *OutboxUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		uc.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			if err := uc.publisher.Close(); err != nil {
+				uc.logger.Warn("Failed to close event publisher", "error", err.Error())
+			}
+			uc.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *OutboxUsecase) tick(ctx context.Context) {
+	for {
+		published, err := uc.repository.ProcessOutbox(ctx, uc.cfg.BatchSize, uc.publisher.Publish, uc.retryAt)
+		if err != nil {
+			if ctx.Err() == nil {
+				uc.logger.Error("Failed to relay outbox", "error", err.Error())
+			}
+			return
+		}
+		if published > 0 {
+			uc.logger.Debug("Published events", "count", published)
+		}
+
+		if published < uc.cfg.BatchSize {
+			break
+		}
+	}
+
+	purged, err := uc.repository.PurgePublished(ctx, time.Now().Add(-uc.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			uc.logger.Error("Failed to purge outbox", "error", err.Error())
+		}
+		return
+	}
+	if purged > 0 {
+		uc.logger.Debug("Purged published events", "count", purged)
+	}
+}
+
+// retryAt returns when a message that failed the given number of times is
+// tried again.
+func (uc *OutboxUsecase) retryAt(attempts int) time.Time {
+	delay := uc.cfg.MinBackoff
+	for i := 1; i < attempts && delay < uc.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return time.Now().Add(min(delay, uc.cfg.MaxBackoff))
+}
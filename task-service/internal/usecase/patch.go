@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+// applyPatch sets the patched fields on t, checking the shape of every
+// value. Rules that depend on other tasks are left to save.
+func applyPatch(t *entities.Task, fields map[string]json.RawMessage, scope string) error {
+	for name, raw := range fields {
+		null := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		var err error
+		switch name {
+		case "title":
+			if null {
+				return app.NewAppError(app.ErrInvalidInput, "title cannot be cleared", nil)
+			}
+			err = decodeField(name, raw, &t.Title)
+			if err == nil && t.Title == "" {
+				return app.NewAppError(app.ErrInvalidInput, "title is required", nil)
+			}
+		case "description":
+			t.Description = ""
+			if !null {
+				err = decodeField(name, raw, &t.Description)
+			}
+		case "deadline":
+			t.Deadline = time.Time{}
+			if !null {
+				err = decodeField(name, raw, &t.Deadline)
+			}
+		case "status_id":
+			if null {
+				return app.NewAppError(app.ErrInvalidInput, "status_id cannot be cleared", nil)
+			}
+			err = decodeField(name, raw, &t.StatusID)
+		case "priority":
+			t.Priority = entities.PriorityNormal
+			if !null {
+				err = decodeField(name, raw, &t.Priority)
+			}
+		case "parent_id":
+			err = decodeOptionalID(name, raw, null, &t.ParentID)
+		case "assignee_id":
+			err = decodeOptionalID(name, raw, null, &t.AssigneeID)
+		case "project_id":
+			err = decodeOptionalID(name, raw, null, &t.ProjectID)
+		case "rrule":
+			if scope != entities.ScopeFuture {
+				return app.NewAppError(app.ErrInvalidInput, "rrule can only be changed with scope=future", nil)
+			}
+			t.RRule = nil
+			if !null {
+				err = decodeField(name, raw, &t.RRule)
+			}
+		case "labels":
+			var labels []string
+			if !null {
+				err = decodeField(name, raw, &labels)
+			}
+			if err == nil {
+				t.Labels, err = normalizeLabels(labels)
+			}
+		case "estimate_points":
+			t.EstimatePoints = nil
+			if !null {
+				err = decodeField(name, raw, &t.EstimatePoints)
+			}
+		case "estimate_hours":
+			t.EstimateHours = nil
+			if !null {
+				err = decodeField(name, raw, &t.EstimateHours)
+			}
+		case "custom_fields":
+			err = mergeCustomFields(t, raw, null)
+		default:
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("field %q cannot be patched", name), nil)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyOperations applies the operations to t one after another, so that
+// a test checks the value left by the operations before it and a later
+// operation on a field overrides an earlier one.
+func applyOperations(t *entities.Task, ops []entities.TaskPatchOperation, scope string) error {
+	for _, op := range ops {
+		fields := map[string]json.RawMessage{op.Field: op.Value}
+
+		var err error
+		if op.Test {
+			err = checkPatchTests(t, fields)
+		} else {
+			err = applyPatch(t, fields, scope)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeCustomFields merges the patched values into the task's custom
+// fields; a null value unsets its field and a null document all of them.
+func mergeCustomFields(t *entities.Task, raw json.RawMessage, null bool) error {
+	if null {
+		t.CustomFields = entities.CustomFields{}
+		return nil
+	}
+
+	var values map[string]interface{}
+	if err := decodeField("custom_fields", raw, &values); err != nil {
+		return err
+	}
+
+	merged := make(entities.CustomFields, len(t.CustomFields)+len(values))
+	for name, v := range t.CustomFields {
+		merged[name] = v
+	}
+	for name, v := range values {
+		if v == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = v
+	}
+	t.CustomFields = merged
+
+	return nil
+}
+
+func decodeField(name string, raw json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("invalid value for %s", name))
+	}
+
+	return nil
+}
+
+func decodeOptionalID(name string, raw json.RawMessage, null bool, id **int) error {
+	if null {
+		*id = nil
+		return nil
+	}
+
+	var v int
+	if err := decodeField(name, raw, &v); err != nil {
+		return err
+	}
+
+	if v < 1 {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("invalid value for %s", name), nil)
+	}
+
+	*id = &v
+	return nil
+}
+
+// checkPatchTests compares the task against the JSON Patch "test"
+// operations, failing with a conflict when any of them does not hold.
+func checkPatchTests(t *entities.Task, tests map[string]json.RawMessage) error {
+	if len(tests) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for name, raw := range tests {
+		var expected interface{}
+		if err := decodeField(name, raw, &expected); err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(fields[name], expected) {
+			return app.NewAppError(app.ErrConflict, fmt.Sprintf("test of %s failed", name), nil)
+		}
+	}
+
+	return nil
+}
+
+// changedColumns lists the columns of t that differ from current. The
+// tracked task fields carry the names of their columns.
+func changedColumns(current, t *entities.Task) []string {
+	changes := entities.DiffTasks(current, t)
+
+	columns := make([]string, 0, len(changes))
+	for name := range changes {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
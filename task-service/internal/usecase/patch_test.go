@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+func TestApplyOperationsInOrder(t *testing.T) {
+	task := &entities.Task{Title: "Draft", Priority: entities.PriorityNormal}
+
+	ops := []entities.TaskPatchOperation{
+		{Field: "title", Value: json.RawMessage(`"Draft"`), Test: true},
+		{Field: "title", Value: json.RawMessage(`"First"`)},
+		{Field: "title", Value: json.RawMessage(`"First"`), Test: true},
+		{Field: "title", Value: json.RawMessage(`"Second"`)},
+	}
+	if err := applyOperations(task, ops, ""); err != nil {
+		t.Fatalf("applyOperations: %v", err)
+	}
+
+	if task.Title != "Second" {
+		t.Fatalf("title = %q, want the value of the last operation", task.Title)
+	}
+}
+
+func TestApplyOperationsTestSeesEarlierOperations(t *testing.T) {
+	task := &entities.Task{Title: "Draft", Priority: entities.PriorityNormal}
+
+	ops := []entities.TaskPatchOperation{
+		{Field: "title", Value: json.RawMessage(`"Final"`)},
+		{Field: "title", Value: json.RawMessage(`"Draft"`), Test: true},
+	}
+	if err := applyOperations(task, ops, ""); err == nil {
+		t.Fatal("test of the replaced value passed, want a conflict")
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/storage"
+)
+
+type TaskPurger interface {
+	Purge(ctx context.Context, before time.Time, limit int) (int, []string, error)
+}
+
+type PurgeUsecaseConfig struct {
+	// Retention is how long deleted tasks stay in the trash.
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+// PurgeUsecase permanently removes tasks that have been in the trash for
+// longer than the retention period, along with their attachment blobs.
+type PurgeUsecase struct {
+	repository TaskPurger
+	store      storage.BlobStore
+	logger     logger.ILogger
+	cfg        PurgeUsecaseConfig
+}
+
+func NewPurgeUsecase(r TaskPurger, s storage.BlobStore, l logger.ILogger, cfg PurgeUsecaseConfig) *PurgeUsecase {
+	return &PurgeUsecase{
+		repository: r,
+		store:      s,
+		logger:     l,
+		cfg:        cfg,
+	}
+}
+
+// Run purges expired tasks every Interval until ctx is cancelled.
+func (uc *PurgeUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		uc.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *PurgeUsecase) tick(ctx context.Context) {
+	before := time.Now().Add(-uc.cfg.Retention)
+
+	for {
+		purged, keys, err := uc.repository.Purge(ctx, before, uc.cfg.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				uc.logger.Error("Failed to purge deleted tasks", "error", err.Error())
+			}
+			return
+		}
+
+		// Blobs are removed once their rows are gone; a failure only
+		// leaves garbage behind in the store.
+		for _, key := range keys {
+			if err := uc.store.Delete(ctx, key); err != nil {
+				uc.logger.Error("Failed to delete attachment blob", "key", key, "error", err.Error())
+			}
+		}
+
+		if purged > 0 {
+			uc.logger.Info("Purged deleted tasks", "count", purged, "attachments", len(keys))
+		}
+
+		if purged < uc.cfg.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/rrule"
+)
+
+func parseRule(s string) (*rrule.Rule, error) {
+	rule, err := rrule.Parse(s)
+	if err != nil {
+		return nil, app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("invalid rrule: %s", err.Error()))
+	}
+
+	return rule, nil
+}
+
+// startSeries makes t the first occurrence of a new recurring series.
+func (uc *TaskUsecase) startSeries(ctx context.Context, t *entities.Task) error {
+	if _, err := parseRule(*t.RRule); err != nil {
+		return err
+	}
+
+	if t.Deadline.IsZero() {
+		return app.NewAppError(app.ErrInvalidInput, "recurring tasks need a deadline", nil)
+	}
+
+	series := entities.TaskSeries{
+		UserID:          t.UserId,
+		RRule:           *t.RRule,
+		DTStart:         t.Deadline,
+		StartOccurrence: 1,
+	}
+	copyTemplate(&series, t)
+
+	if err := uc.repository.CreateSeries(ctx, &series); err != nil {
+		return err
+	}
+
+	t.SeriesID = &series.ID
+	t.Occurrence = 1
+
+	return nil
+}
+
+// updateSeries applies an edit of t made for "this and all future"
+// occurrences to the series template and the occurrences after t.
+func (uc *TaskUsecase) updateSeries(ctx context.Context, actorID int, current, t *entities.Task) error {
+	series, err := uc.repository.GetSeries(ctx, *t.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	shift := t.Deadline.Sub(current.Deadline)
+	if shift != 0 || series.RRule != *t.RRule {
+		// The schedule changes from this occurrence on, so it becomes the
+		// new starting point of the rule.
+		series.DTStart = t.Deadline
+		series.StartOccurrence = t.Occurrence
+	}
+
+	series.RRule = *t.RRule
+	copyTemplate(series, t)
+
+	if err := uc.repository.UpdateSeries(ctx, series); err != nil {
+		return err
+	}
+
+	return uc.repository.UpdateOccurrencesAfter(ctx, series, t.Occurrence, shift, actorID)
+}
+
+// spawnNext creates the occurrence following t, which actorID has just
+// completed, unless the rule has ended or it already exists.
+func (uc *TaskUsecase) spawnNext(ctx context.Context, actorID int, t *entities.Task) error {
+	if t.SeriesID == nil {
+		return nil
+	}
+
+	series, err := uc.repository.GetSeries(ctx, *t.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	rule, err := parseRule(series.RRule)
+	if err != nil {
+		return err
+	}
+
+	next := t.Occurrence + 1
+	if rule.Count > 0 && next > rule.Count {
+		return nil
+	}
+	// COUNT is about the whole series, which Occurrence cannot know once
+	// the series has been rebased, so it was checked above.
+	rule.Count = 0
+
+	deadline, ok := rule.Occurrence(series.DTStart, next-series.StartOccurrence+1)
+	if !ok {
+		return nil
+	}
+
+	exists, err := uc.repository.OccurrenceExists(ctx, series.ID, next)
+	if err != nil || exists {
+		return err
+	}
+
+	// The template of the series holds what edits of all future
+	// occurrences changed, the rest carries over from t.
+	task := entities.Task{
+		UserId:         series.UserID,
+		ParentID:       series.ParentID,
+		AssigneeID:     series.AssigneeID,
+		ProjectID:      series.ProjectID,
+		Title:          series.Title,
+		Description:    series.Description,
+		Priority:       series.Priority,
+		Deadline:       deadline,
+		RRule:          &series.RRule,
+		SeriesID:       &series.ID,
+		Occurrence:     next,
+		Labels:         t.Labels,
+		EstimatePoints: t.EstimatePoints,
+		EstimateHours:  t.EstimateHours,
+		CustomFields:   t.CustomFields,
+	}
+
+	if err := uc.checkNew(ctx, actorID, &task, t); err != nil {
+		return err
+	}
+
+	if err := uc.repository.Create(ctx, &task, actorID); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Spawned next occurrence of recurring task", "series_id", series.ID, "task_id", task.ID, "occurrence", next)
+
+	return nil
+}
+
+func copyTemplate(s *entities.TaskSeries, t *entities.Task) {
+	s.Title = t.Title
+	s.Description = t.Description
+	s.Priority = t.Priority
+	s.AssigneeID = t.AssigneeID
+	s.ProjectID = t.ProjectID
+	s.ParentID = t.ParentID
+}
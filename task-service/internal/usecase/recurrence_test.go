@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+func TestSpawnNextCarriesFieldsOver(t *testing.T) {
+	repo := &stubTaskRepository{}
+	uc := NewTaskUsecase(repo, nil, stubUsers{}, nopLogger{}, TaskUsecaseConfig{})
+
+	rule, points, assignee := "FREQ=WEEKLY", 3.0, 2
+	task, err := uc.Create(context.Background(), &entities.CreateTaskDto{
+		UserID:         1,
+		Title:          "Weekly report",
+		Deadline:       time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		RRule:          &rule,
+		AssigneeID:     &assignee,
+		Labels:         []string{"report"},
+		EstimatePoints: &points,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := uc.spawnNext(context.Background(), 1, task); err != nil {
+		t.Fatalf("spawnNext: %v", err)
+	}
+	if len(repo.created) != 2 {
+		t.Fatalf("created %d tasks, want the next occurrence", len(repo.created))
+	}
+
+	next := repo.created[1]
+	if next.Occurrence != 2 || !next.Deadline.Equal(task.Deadline.AddDate(0, 0, 7)) {
+		t.Fatalf("next occurrence %d due %v, want the 2nd a week later", next.Occurrence, next.Deadline)
+	}
+	if !reflect.DeepEqual([]string(next.Labels), []string{"report"}) || next.EstimatePoints == nil || *next.EstimatePoints != points {
+		t.Fatalf("next occurrence has labels %v and estimate %v, want those of the completed one", next.Labels, next.EstimatePoints)
+	}
+	if next.AssigneeID == nil || *next.AssigneeID != assignee || next.Priority != entities.PriorityNormal {
+		t.Fatalf("next occurrence = %+v, want the template of the series", next)
+	}
+
+	// A second completion of the same occurrence spawns nothing.
+	if err := uc.spawnNext(context.Background(), 1, task); err != nil || len(repo.created) != 2 {
+		t.Fatalf("spawnNext again: err = %v, %d tasks", err, len(repo.created))
+	}
+}
+
+func TestSpawnNextValidates(t *testing.T) {
+	repo := &stubTaskRepository{}
+	uc := NewTaskUsecase(repo, nil, stubUsers{}, nopLogger{}, TaskUsecaseConfig{})
+
+	rule := "FREQ=DAILY"
+	task, err := uc.Create(context.Background(), &entities.CreateTaskDto{
+		UserID:   1,
+		Title:    "Stand-up",
+		Deadline: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		RRule:    &rule,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	repo.series[0].Priority = "someday"
+
+	err = uc.spawnNext(context.Background(), 1, task)
+	var appErr *app.AppError
+	if !errors.As(err, &appErr) || appErr.Type != app.ErrInvalidInput {
+		t.Fatalf("spawnNext: err = %v, want invalid input", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d tasks, want none after the first", len(repo.created))
+	}
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+	"github.com/dielit66/task-management-system/internal/notifier"
+)
+
+// maxReminderOffsets bounds how many reminders a user can ask for per task.
+const maxReminderOffsets = 10
+
+type ReminderRepository interface {
+	GetSettings(ctx context.Context, userID int) ([]int, error)
+	SaveSettings(ctx context.Context, s *entities.ReminderSettings) error
+	ProcessDue(ctx context.Context, defaultOffsets []int, lookback time.Duration, limit int, channels []string,
+		send func(ctx context.Context, rem *entities.Reminder, channel string) error) (int, error)
+}
+
+type ReminderUsecaseConfig struct {
+	// DefaultOffsets are used for users without their own settings, in
+	// minutes before the deadline.
+	DefaultOffsets []int
+	Interval       time.Duration
+	Lookback       time.Duration
+	BatchSize      int
+}
+
+type ReminderUsecase struct {
+	repository ReminderRepository
+	notifiers  map[string]notifier.Notifier
+	channels   []string
+	logger     logger.ILogger
+	cfg        ReminderUsecaseConfig
+}
+
+func NewReminderUsecase(r ReminderRepository, channels []notifier.Channel, l logger.ILogger, cfg ReminderUsecaseConfig) *ReminderUsecase {
+	uc := &ReminderUsecase{
+		repository: r,
+		notifiers:  make(map[string]notifier.Notifier, len(channels)),
+		logger:     l,
+		cfg:        cfg,
+	}
+
+	for _, c := range channels {
+		uc.notifiers[c.Name] = c.Notifier
+		uc.channels = append(uc.channels, c.Name)
+	}
+
+	return uc
+}
+
+func (uc *ReminderUsecase) GetSettings(ctx context.Context, userID int) (*entities.ReminderSettings, error) {
+	offsets, err := uc.repository.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offsets == nil {
+		offsets = uc.cfg.DefaultOffsets
+	}
+
+	return &entities.ReminderSettings{UserID: userID, OffsetsMinutes: offsets}, nil
+}
+
+func (uc *ReminderUsecase) UpdateSettings(ctx context.Context, s *entities.ReminderSettings) error {
+	if len(s.OffsetsMinutes) > maxReminderOffsets {
+		return app.NewAppError(app.ErrInvalidInput, "too many reminder offsets", nil)
+	}
+
+	seen := map[int]bool{}
+	for _, o := range s.OffsetsMinutes {
+		if seen[o] {
+			return app.NewAppError(app.ErrInvalidInput, "reminder offsets must be unique", nil)
+		}
+		seen[o] = true
+	}
+
+	if s.OffsetsMinutes == nil {
+		s.OffsetsMinutes = []int{}
+	}
+
+	return uc.repository.SaveSettings(ctx, s)
+}
+
+// Run sends due reminders every configured interval until ctx is done.
+func (uc *ReminderUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		uc.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *ReminderUsecase) tick(ctx context.Context) {
+	for {
+		sent, err := uc.repository.ProcessDue(ctx, uc.cfg.DefaultOffsets, uc.cfg.Lookback, uc.cfg.BatchSize, uc.channels, uc.send)
+		if err != nil {
+			if ctx.Err() == nil {
+				uc.logger.Error("Failed to process due reminders", "error", err.Error())
+			}
+			return
+		}
+
+		if sent > 0 {
+			uc.logger.Info("Sent task reminders", "count", sent)
+		}
+
+		// A partial batch means everything due has been handled.
+		if sent < uc.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (uc *ReminderUsecase) send(ctx context.Context, rem *entities.Reminder, channel string) error {
+	return uc.notifiers[channel].Notify(ctx, rem)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+const (
+	// defaultStatsDays is the burndown length when no range is given.
+	defaultStatsDays = 30
+	// maxStatsDays bounds the burndown.
+	maxStatsDays = 366
+)
+
+type StatsRepository interface {
+	GetStats(ctx context.Context, f entities.StatsFilter) (*entities.Stats, error)
+}
+
+type StatsUsecase struct {
+	repository StatsRepository
+	projects   ProjectAccess
+	logger     logger.ILogger
+}
+
+func NewStatsUsecase(r StatsRepository, p ProjectAccess, l logger.ILogger) *StatsUsecase {
+	return &StatsUsecase{
+		repository: r,
+		projects:   p,
+		logger:     l,
+	}
+}
+
+// Get computes the statistics of the filter. The range is widened to whole
+// UTC days and defaults to the last 30 days, today included.
+func (uc *StatsUsecase) Get(ctx context.Context, f entities.StatsFilter) (*entities.Stats, error) {
+	if f.ProjectID != nil {
+		if err := requireRole(ctx, uc.projects, *f.ProjectID, f.UserID, entities.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.To.IsZero() {
+		f.To = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	} else if day := f.To.UTC().Truncate(24 * time.Hour); !day.Equal(f.To) {
+		f.To = day.AddDate(0, 0, 1)
+	}
+
+	if f.From.IsZero() {
+		f.From = f.To.AddDate(0, 0, -defaultStatsDays)
+	} else {
+		f.From = f.From.UTC().Truncate(24 * time.Hour)
+	}
+
+	if !f.To.After(f.From) {
+		return nil, app.NewAppError(app.ErrInvalidInput, "to must be after from", nil)
+	}
+	if f.To.Sub(f.From) > maxStatsDays*24*time.Hour {
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("the range is limited to %d days", maxStatsDays), nil)
+	}
+
+	stats, err := uc.repository.GetStats(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := 0
+	for _, s := range stats.ByStatus {
+		stats.Total += s.Count
+		if s.StatusID == entities.StatusCompleted {
+			completed = s.Count
+		}
+	}
+	if stats.Total > 0 {
+		stats.CompletionRate = float64(completed) / float64(stats.Total)
+	}
+
+	return stats, nil
+}
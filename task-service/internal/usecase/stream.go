@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// listenRetryDelay is the pause before listening again after the listener
+// failed outright.
+const listenRetryDelay = 5 * time.Second
+
+type StreamRepository interface {
+	LastStreamEventID(ctx context.Context) (int64, error)
+	GetStreamEvents(ctx context.Context, ids []int64) ([]*entities.StreamEvent, error)
+	GetStreamEventsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]*entities.StreamEvent, error)
+}
+
+type EventListener interface {
+	Listen(ctx context.Context, notify func(ids []int64), reconnected func()) error
+}
+
+type StreamUsecaseConfig struct {
+	// BufferSize is how many events a subscriber may fall behind before it
+	// is dropped.
+	BufferSize int
+	// ReplayLimit caps the events replayed to a resuming subscriber.
+	ReplayLimit int
+}
+
+// StreamUsecase fans task events out to live subscribers. Events reach it
+// through the listener from whichever replica committed them.
+type StreamUsecase struct {
+	repository StreamRepository
+	listener   EventListener
+	logger     logger.ILogger
+	cfg        StreamUsecaseConfig
+
+	mu          sync.Mutex
+	subscribers map[*entities.Subscription]struct{}
+	closed      bool
+	// lastID is the highest event id dispatched, from which the gap left
+	// by a listener reconnect is filled. Run starts it at the last event
+	// committed before it listens.
+	lastID  int64
+	started bool
+}
+
+func NewStreamUsecase(r StreamRepository, el EventListener, l logger.ILogger, cfg StreamUsecaseConfig) *StreamUsecase {
+	return &StreamUsecase{
+		repository:  r,
+		listener:    el,
+		logger:      l,
+		cfg:         cfg,
+		subscribers: make(map[*entities.Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber and, when it resumes after
+// lastEventID, returns the events it missed. The subscription is live
+// before the backlog is read, so the same event may show up in both.
+func (uc *StreamUsecase) Subscribe(ctx context.Context, userID int, lastEventID int64) (*entities.Subscription, []*entities.StreamEvent, error) {
+	sub := &entities.Subscription{UserID: userID, Events: make(chan *entities.StreamEvent, uc.cfg.BufferSize)}
+
+	uc.mu.Lock()
+	if uc.closed {
+		close(sub.Events)
+	} else {
+		uc.subscribers[sub] = struct{}{}
+	}
+	uc.mu.Unlock()
+
+	if lastEventID <= 0 {
+		return sub, nil, nil
+	}
+
+	backlog, err := uc.repository.GetStreamEventsAfter(ctx, userID, lastEventID, uc.cfg.ReplayLimit)
+	if err != nil {
+		uc.Unsubscribe(sub)
+		return nil, nil, err
+	}
+
+	return sub, backlog, nil
+}
+
+func (uc *StreamUsecase) Unsubscribe(sub *entities.Subscription) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.subscribers[sub]; ok {
+		delete(uc.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// Run dispatches events until ctx is cancelled, then closes every
+// subscription so that open streams end.
+func (uc *StreamUsecase) Run(ctx context.Context) {
+	defer uc.closeAll()
+
+	for {
+		err := uc.start(ctx)
+		if err == nil {
+			err = uc.listener.Listen(ctx, func(ids []int64) {
+				uc.dispatch(ctx, ids)
+			}, func() {
+				uc.catchUp(ctx)
+			})
+		}
+		if ctx.Err() != nil {
+			uc.logger.Info("Task event stream stopped")
+			return
+		}
+
+		uc.logger.Error("Task event listener failed", "error", errString(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// start sets lastID to the last event committed so far, unless that was
+// done before. Without it a reconnect before the first event is dispatched
+// would not know where to catch up from.
+func (uc *StreamUsecase) start(ctx context.Context) error {
+	uc.mu.Lock()
+	started := uc.started
+	uc.mu.Unlock()
+
+	if started {
+		return nil
+	}
+
+	lastID, err := uc.repository.LastStreamEventID(ctx)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.lastID = max(uc.lastID, lastID)
+	uc.started = true
+
+	return nil
+}
+
+func (uc *StreamUsecase) dispatch(ctx context.Context, ids []int64) {
+	events, err := uc.repository.GetStreamEvents(ctx, ids)
+	if err != nil {
+		uc.logger.Error("Failed to load task events", "ids", ids, "error", err.Error())
+		return
+	}
+
+	uc.publish(events)
+}
+
+// catchUp dispatches the events committed after the last one seen, which
+// were announced while the listener was disconnected.
+func (uc *StreamUsecase) catchUp(ctx context.Context) {
+	uc.mu.Lock()
+	lastID := uc.lastID
+	uc.mu.Unlock()
+
+	for {
+		events, err := uc.repository.GetStreamEventsAfter(ctx, 0, lastID, uc.cfg.ReplayLimit)
+		if err != nil {
+			uc.logger.Error("Failed to catch up on task events", "after", lastID, "error", err.Error())
+			return
+		}
+
+		uc.publish(events)
+		if len(events) < uc.cfg.ReplayLimit {
+			return
+		}
+		lastID = int64(events[len(events)-1].ID)
+	}
+}
+
+// publish hands every event to the subscribers that can see its task.
+// Subscribers whose buffer is full are dropped rather than slowing down
+// everyone else.
+func (uc *StreamUsecase) publish(events []*entities.StreamEvent) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for _, ev := range events {
+		if int64(ev.ID) > uc.lastID {
+			uc.lastID = int64(ev.ID)
+		}
+
+		for sub := range uc.subscribers {
+			if !slices.Contains(ev.Viewers, sub.UserID) {
+				continue
+			}
+
+			select {
+			case sub.Events <- ev:
+			default:
+				uc.logger.Warn("Dropping slow stream subscriber", "user_id", sub.UserID, "event_id", ev.ID)
+				delete(uc.subscribers, sub)
+				close(sub.Events)
+			}
+		}
+	}
+}
+
+func (uc *StreamUsecase) closeAll() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.closed = true
+	for sub := range uc.subscribers {
+		delete(uc.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "unknown error"
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// stubStreamRepository records after which event catching up starts.
+type stubStreamRepository struct {
+	lastID int64
+	after  []int64
+}
+
+func (r *stubStreamRepository) LastStreamEventID(ctx context.Context) (int64, error) {
+	return r.lastID, nil
+}
+
+func (r *stubStreamRepository) GetStreamEvents(ctx context.Context, ids []int64) ([]*entities.StreamEvent, error) {
+	return nil, nil
+}
+
+func (r *stubStreamRepository) GetStreamEventsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]*entities.StreamEvent, error) {
+	r.after = append(r.after, afterID)
+	return nil, nil
+}
+
+// reconnectingListener reconnects once before any event arrives, then
+// stops the stream.
+type reconnectingListener struct {
+	cancel context.CancelFunc
+}
+
+func (l reconnectingListener) Listen(ctx context.Context, notify func(ids []int64), reconnected func()) error {
+	reconnected()
+	l.cancel()
+	return ctx.Err()
+}
+
+func TestCatchUpBeforeFirstEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := &stubStreamRepository{lastID: 41}
+	uc := NewStreamUsecase(repo, reconnectingListener{cancel: cancel}, nopLogger{}, StreamUsecaseConfig{BufferSize: 1, ReplayLimit: 10})
+	uc.Run(ctx)
+
+	if len(repo.after) != 1 || repo.after[0] != 41 {
+		t.Fatalf("caught up after %v, want after the last event committed at startup", repo.after)
+	}
+}
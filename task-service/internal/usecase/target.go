@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice
+// though not in net.IP.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// targetPolicy keeps webhook requests out of the service's own network:
+// loopback, private, link-local (cloud metadata at 169.254.169.254
+// included), multicast and unspecified addresses are refused unless they
+// fall into an allowed network.
+type targetPolicy struct {
+	allowed []netip.Prefix
+}
+
+func newTargetPolicy(networks []string) (*targetPolicy, error) {
+	p := &targetPolicy{}
+	for _, n := range networks {
+		prefix, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed webhook network %q: %w", n, err)
+		}
+		p.allowed = append(p.allowed, prefix.Masked())
+	}
+
+	return p, nil
+}
+
+func (p *targetPolicy) permits(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return !(addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr))
+}
+
+// checkHost rejects URLs whose host is a refused address or names the
+// local machine. Other host names are only resolved, and checked, when a
+// request is made.
+func (p *targetPolicy) checkHost(host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !p.permits(addr) {
+			return fmt.Errorf("address %s is not allowed", addr)
+		}
+		return nil
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		if !p.permits(netip.MustParseAddr("127.0.0.1")) {
+			return fmt.Errorf("host %s is not allowed", host)
+		}
+	}
+
+	return nil
+}
+
+// control runs before every connection is made, after name resolution,
+// so host names resolving to refused addresses are caught as well.
+func (p *targetPolicy) control(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !p.permits(ap.Addr()) {
+		return fmt.Errorf("webhook target %s is not allowed", ap.Addr())
+	}
+
+	return nil
+}
+
+// client returns an HTTP client that connects only to permitted
+// addresses, directly rather than through an environment proxy, and does
+// not follow redirects: a 3xx response counts as a failed delivery.
+func (p *targetPolicy) client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: p.control}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestTargetPolicyPermits(t *testing.T) {
+	p, err := newTargetPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refused := []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"fe80::1", "fc00::1", "100.64.0.1", "0.0.0.0", "::ffff:127.0.0.1", "224.0.0.1"}
+	for _, a := range refused {
+		if p.permits(netip.MustParseAddr(a)) {
+			t.Errorf("%s is permitted", a)
+		}
+	}
+
+	for _, a := range []string{"93.184.216.34", "2606:2800:220:1::1"} {
+		if !p.permits(netip.MustParseAddr(a)) {
+			t.Errorf("%s is refused", a)
+		}
+	}
+
+	for _, host := range []string{"127.0.0.1", "169.254.169.254", "localhost", "api.localhost"} {
+		if p.checkHost(host) == nil {
+			t.Errorf("host %s passes the check", host)
+		}
+	}
+	if err := p.checkHost("example.com"); err != nil {
+		t.Errorf("example.com: %v", err)
+	}
+}
+
+func TestTargetPolicyClient(t *testing.T) {
+	redirect := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/", http.StatusFound))
+	defer redirect.Close()
+
+	p, err := newTargetPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.client(time.Second).Get(redirect.URL); err == nil {
+		t.Fatal("request to a loopback address succeeded")
+	}
+
+	p, err = newTargetPolicy([]string{"127.0.0.0/8", "::1/128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.client(time.Second).Get(redirect.URL)
+	if err != nil {
+		t.Fatalf("request to an allowed network: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("status = %d, want the redirect itself", resp.StatusCode)
+	}
+}
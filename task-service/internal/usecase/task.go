@@ -2,86 +2,829 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
 	"github.com/dielit66/task-management-system/internal/logger"
 )
 
 type UserRepository interface {
 	GetById(ctx context.Context, id int) (*entities.Task, error)
-	GetAllByUserId(ctx context.Context, id int) ([]*entities.Task, error)
-	Create(ctx context.Context, t *entities.Task) error
-	Update(ctx context.Context, t *entities.Task) error
-	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, f entities.TaskFilter) ([]*entities.Task, error)
+	Each(ctx context.Context, f entities.TaskFilter, fn func(t *entities.Task) error) error
+	Create(ctx context.Context, t *entities.Task, actorID int) error
+	Update(ctx context.Context, t *entities.Task, actorID int) error
+	UpdateColumns(ctx context.Context, t *entities.Task, columns []string, actorID int) error
+	Delete(ctx context.Context, id, version, actorID int) error
+	GetDescendants(ctx context.Context, id int) ([]*entities.Task, error)
+	IsAncestor(ctx context.Context, ancestorID, id int) (bool, error)
+	CountOpenChildren(ctx context.Context, id int) (int, error)
+	LockDependencies(ctx context.Context) error
+	AddDependency(ctx context.Context, d entities.Dependency) error
+	RemoveDependency(ctx context.Context, d entities.Dependency) error
+	GetDependencyComponent(ctx context.Context, id int) ([]entities.Dependency, error)
+	CountOpenBlockers(ctx context.Context, id int) (int, error)
+	GetByIds(ctx context.Context, ids []int) ([]*entities.Task, error)
+	Move(ctx context.Context, t *entities.Task, statusID int, prevID, nextID *int, version, actorID int) error
+	CreateSeries(ctx context.Context, s *entities.TaskSeries) error
+	GetSeries(ctx context.Context, id int) (*entities.TaskSeries, error)
+	UpdateSeries(ctx context.Context, s *entities.TaskSeries) error
+	UpdateOccurrencesAfter(ctx context.Context, s *entities.TaskSeries, occurrence int, shift time.Duration, actorID int) error
+	OccurrenceExists(ctx context.Context, seriesID, occurrence int) (bool, error)
+	ResetReminders(ctx context.Context, id int) error
+	GetHistory(ctx context.Context, taskID, limit, offset int) ([]*entities.TaskEvent, int, error)
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+	GetDeleted(ctx context.Context, id int) (*entities.Task, error)
+	Restore(ctx context.Context, id, actorID int) error
+}
+
+type UserClient interface {
+	GetUser(ctx context.Context, id int) (*entities.User, error)
+}
+
+type ProjectAccess interface {
+	GetById(ctx context.Context, id int) (*entities.Project, error)
+	GetMemberRole(ctx context.Context, projectID, userID int) (string, error)
+	GetFields(ctx context.Context, projectID int) ([]*entities.ProjectField, error)
+}
+
+const maxLabelLength = 50
+
+type TaskUsecaseConfig struct {
+	// RequireClosedSubtasks forbids completing a task while any of its
+	// subtasks are still open.
+	RequireClosedSubtasks bool
+	// RequireProject rejects tasks that do not belong to a project.
+	RequireProject bool
+	// MaxBulkOperations caps the number of operations of a bulk request.
+	MaxBulkOperations int
+	// MaxImportRows caps the number of rows of an import.
+	MaxImportRows int
 }
 
 type TaskUsecase struct {
 	repository UserRepository
+	projects   ProjectAccess
+	users      UserClient
 	logger     logger.ILogger
+	cfg        TaskUsecaseConfig
 }
 
-func NewTaskUsecase(r UserRepository, l logger.ILogger) *TaskUsecase {
+func NewTaskUsecase(r UserRepository, p ProjectAccess, u UserClient, l logger.ILogger, cfg TaskUsecaseConfig) *TaskUsecase {
 	return &TaskUsecase{
 		repository: r,
+		projects:   p,
+		users:      u,
 		logger:     l,
+		cfg:        cfg,
 	}
 }
 
-func (uc *TaskUsecase) GetAllById(ctx context.Context, id int) ([]*entities.Task, error) {
-	tasks, err := uc.repository.GetAllByUserId(ctx, id)
+func (uc *TaskUsecase) List(ctx context.Context, f entities.TaskFilter) ([]*entities.Task, error) {
+	if f.ProjectID != nil {
+		if err := requireRole(ctx, uc.projects, *f.ProjectID, f.UserID, entities.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.resolveFieldFilter(ctx, &f); err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.repository.List(ctx, f)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if f.Sort == entities.SortUrgency {
+		sortByUrgency(tasks, time.Now())
+	}
+
+	if desc, field, ok := entities.SortField(f.Sort); ok {
+		if err := uc.sortByField(ctx, tasks, f.ProjectID, field, desc); err != nil {
+			return nil, err
+		}
+	}
+
 	return tasks, nil
 }
 
-func (uc *TaskUsecase) GetById(ctx context.Context, id int) (*entities.Task, error) {
+// Focus returns the limit most urgent open tasks the user should work on
+// next: the ones assigned to them and their own unassigned tasks.
+func (uc *TaskUsecase) Focus(ctx context.Context, userID, limit int) ([]*entities.Task, error) {
+	tasks, err := uc.repository.List(ctx, entities.TaskFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	mine := make([]*entities.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.StatusID == entities.StatusCompleted {
+			continue
+		}
+		if (t.AssigneeID != nil && *t.AssigneeID == userID) || (t.AssigneeID == nil && t.UserId == userID) {
+			mine = append(mine, t)
+		}
+	}
+
+	sortByUrgency(mine, time.Now())
+
+	if len(mine) > limit {
+		mine = mine[:limit]
+	}
+
+	return mine, nil
+}
+
+// GetById returns the task with its progress for a user who may at least
+// view it.
+func (uc *TaskUsecase) GetById(ctx context.Context, userID, id int) (*entities.Task, error) {
 	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.withProgress(ctx, task)
+}
+
+// withProgress rolls the progress of the task's subtree up into the task.
+func (uc *TaskUsecase) withProgress(ctx context.Context, task *entities.Task) (*entities.Task, error) {
+	descendants, err := uc.repository.GetDescendants(ctx, task.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
+	buildTree(task, descendants)
+	task.Children = nil
+
 	return task, nil
 }
 
-func (uc *TaskUsecase) Create(ctx context.Context, t *entities.CreateTaskDto) error {
+func (uc *TaskUsecase) Create(ctx context.Context, t *entities.CreateTaskDto) (*entities.Task, error) {
 
 	task := entities.Task{
-		UserId: t.UserID,
-		Title:  t.Title,
+		UserId:     t.UserID,
+		ParentID:   t.ParentID,
+		AssigneeID: t.AssigneeID,
+		ProjectID:  t.ProjectID,
+		Title:      t.Title,
 
 		Description: t.Description,
 		Deadline:    t.Deadline,
+		Priority:    t.Priority,
+		RRule:       t.RRule,
+		Labels:      t.Labels,
+
+		EstimatePoints: t.EstimatePoints,
+		EstimateHours:  t.EstimateHours,
+		CustomFields:   t.CustomFields,
 	}
 
-	err := uc.repository.Create(ctx, &task)
+	if err := uc.checkNew(ctx, t.UserID, &task, nil); err != nil {
+		return nil, err
+	}
 
+	err := uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		if task.RRule != nil {
+			if err := uc.startSeries(ctx, &task); err != nil {
+				return err
+			}
+		}
+
+		return uc.repository.Create(ctx, &task, t.UserID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// checkNew validates a task userID is about to create and brings it to its
+// stored form. Subtasks without a project land in their parent's. Custom
+// field values carried over from the previous task of a series are
+// checked like those of an update of it.
+func (uc *TaskUsecase) checkNew(ctx context.Context, userID int, task, previous *entities.Task) error {
+	if task.Priority == "" {
+		task.Priority = entities.PriorityNormal
+	}
+
+	if err := checkPriority(task.Priority); err != nil {
+		return err
+	}
+
+	if err := checkEstimates(task); err != nil {
+		return err
+	}
+
+	labels, err := normalizeLabels(task.Labels)
 	if err != nil {
 		return err
 	}
+	task.Labels = labels
+
+	if task.ParentID != nil {
+		parent, err := uc.parentTask(ctx, userID, *task.ParentID)
+		if err != nil {
+			return err
+		}
+
+		if task.ProjectID == nil {
+			task.ProjectID = parent.ProjectID
+		}
+	}
+
+	if err := uc.checkProject(ctx, userID, task.ProjectID); err != nil {
+		return err
+	}
+
+	if err := uc.checkCustomFields(ctx, task, previous); err != nil {
+		return err
+	}
+
+	if task.AssigneeID != nil {
+		if err := uc.checkTaskAssignee(ctx, task.ProjectID, *task.AssigneeID); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (uc *TaskUsecase) Update(ctx context.Context, t *entities.Task) error {
-	err := uc.repository.Update(ctx, t)
+// CreateTree creates the task and its subtasks, each with the checks of
+// Create, in one transaction. Subtasks without a project land in their
+// parent's. The returned task lists the created subtasks as children.
+func (uc *TaskUsecase) CreateTree(ctx context.Context, tree *entities.CreateTaskTreeDto) (*entities.Task, error) {
+	var root *entities.Task
+	err := uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		root, err = uc.createTree(ctx, tree)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+func (uc *TaskUsecase) createTree(ctx context.Context, tree *entities.CreateTaskTreeDto) (*entities.Task, error) {
+	task, err := uc.Create(ctx, &tree.CreateTaskDto)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range tree.Subtasks {
+		dto := *sub
+		dto.ParentID = &task.ID
+
+		child, err := uc.createTree(ctx, &dto)
+		if err != nil {
+			return nil, err
+		}
+		task.Children = append(task.Children, child)
+	}
+
+	return task, nil
+}
+
+// Update replaces the task. For recurring tasks scope decides whether the
+// edit applies to this occurrence only or to all future ones as well; the
+// recurrence rule itself can only be changed for future occurrences. A
+// non-zero t.Version makes the update conditional on that version.
+func (uc *TaskUsecase) Update(ctx context.Context, userID int, t *entities.Task, scope string) error {
+	current, err := uc.repository.GetById(ctx, t.ID)
 
 	if err != nil {
 		return err
 	}
 
+	if err := requireTaskAccess(ctx, uc.projects, current, userID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	if t.Version != 0 && t.Version != current.Version {
+		return app.NewAppError(app.ErrPrecondition, fmt.Sprintf("task %d has been modified, current version is %d", t.ID, current.Version), nil)
+	}
+
+	// Labels are managed through PATCH and bulk operations.
+	t.UserId, t.Labels = current.UserId, current.Labels
+	t.SeriesID, t.Occurrence = current.SeriesID, current.Occurrence
+	if scope != entities.ScopeFuture {
+		t.RRule = current.RRule
+	}
+
+	if t.StatusID == 0 {
+		t.StatusID = current.StatusID
+	}
+
+	if t.Priority == "" {
+		t.Priority = current.Priority
+	}
+
+	if t.CustomFields == nil {
+		t.CustomFields = current.CustomFields
+	}
+
+	return uc.save(ctx, userID, current, t, scope, false)
+}
+
+// Patch applies a partial update to the task and returns the result. Only
+// the columns that actually change are written. A non-zero version makes
+// the patch conditional on that version.
+func (uc *TaskUsecase) Patch(ctx context.Context, userID, id, version int, p *entities.TaskPatch, scope string) (*entities.Task, error) {
+	current, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, current, userID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != current.Version {
+		return nil, app.NewAppError(app.ErrPrecondition, fmt.Sprintf("task %d has been modified, current version is %d", id, current.Version), nil)
+	}
+
+	if err := checkPatchTests(current, p.Tests); err != nil {
+		return nil, err
+	}
+
+	t := *current
+	if err := applyPatch(&t, p.Fields, scope); err != nil {
+		return nil, err
+	}
+
+	if err := applyOperations(&t, p.Operations, scope); err != nil {
+		return nil, err
+	}
+	t.Version = version
+
+	if err := uc.save(ctx, userID, current, &t, scope, true); err != nil {
+		return nil, err
+	}
+
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.withProgress(ctx, task)
+}
+
+// save validates t against the stored current task, writes it and runs
+// the follow-ups of the change. With partial set only the columns that
+// differ from current are written.
+func (uc *TaskUsecase) save(ctx context.Context, userID int, current, t *entities.Task, scope string, partial bool) error {
+	if err := checkPriority(t.Priority); err != nil {
+		return err
+	}
+
+	if err := checkEstimates(t); err != nil {
+		return err
+	}
+
+	if t.ParentID != nil {
+		if err := uc.checkParent(ctx, userID, t.ID, *t.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if !sameID(t.ProjectID, current.ProjectID) {
+		if err := uc.checkProject(ctx, userID, t.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	if t.AssigneeID != nil && (!sameID(t.AssigneeID, current.AssigneeID) || !sameID(t.ProjectID, current.ProjectID)) {
+		if err := uc.checkTaskAssignee(ctx, t.ProjectID, *t.AssigneeID); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.checkCustomFields(ctx, t, current); err != nil {
+		return err
+	}
+
+	if err := uc.checkTransition(ctx, current, t.StatusID); err != nil {
+		return err
+	}
+
+	if scope == entities.ScopeFuture && t.RRule != nil && t.SeriesID != nil {
+		if _, err := parseRule(*t.RRule); err != nil {
+			return err
+		}
+	}
+
+	// The task and its series are written in one transaction so that a
+	// failing series write does not leave the task half-updated.
+	return uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		continueSeries := false
+		if scope == entities.ScopeFuture {
+			switch {
+			case t.RRule == nil:
+				t.SeriesID, t.Occurrence = nil, 0
+			case t.SeriesID == nil:
+				if err := uc.startSeries(ctx, t); err != nil {
+					return err
+				}
+			default:
+				continueSeries = true
+			}
+		}
+
+		var err error
+		if partial {
+			columns := changedColumns(current, t)
+			if len(columns) == 0 {
+				return nil
+			}
+			err = uc.repository.UpdateColumns(ctx, t, columns, userID)
+		} else {
+			err = uc.repository.Update(ctx, t, userID)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !t.Deadline.Equal(current.Deadline) {
+			if err := uc.repository.ResetReminders(ctx, t.ID); err != nil {
+				return err
+			}
+		}
+
+		if continueSeries {
+			if err := uc.updateSeries(ctx, userID, current, t); err != nil {
+				return err
+			}
+		}
+
+		if t.StatusID == entities.StatusCompleted && current.StatusID != entities.StatusCompleted {
+			if err := uc.spawnNext(ctx, userID, t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete moves the task and its subtasks to the trash. A non-zero version
+// makes the deletion conditional on that version.
+func (uc *TaskUsecase) Delete(ctx context.Context, userID, id, version int) error {
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	return uc.repository.Delete(ctx, id, version, userID)
+}
+
+// Trash lists the deleted tasks visible to the user, most recently deleted
+// first.
+func (uc *TaskUsecase) Trash(ctx context.Context, userID int) ([]*entities.Task, error) {
+	return uc.repository.List(ctx, entities.TaskFilter{UserID: userID, Deleted: true})
+}
+
+// Restore takes a task out of the trash along with the subtasks deleted
+// together with it.
+func (uc *TaskUsecase) Restore(ctx context.Context, userID, id int) (*entities.Task, error) {
+	task, err := uc.repository.GetDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repository.Restore(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.GetById(ctx, userID, id)
+}
+
+// Move puts the task into a board column between the given neighbours,
+// changing its status when the column differs. A non-zero version must
+// match the stored one.
+func (uc *TaskUsecase) Move(ctx context.Context, userID, id, version int, dto *entities.MoveTaskDto) (*entities.Task, error) {
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if dto.StatusID == 0 {
+		dto.StatusID = task.StatusID
+	}
+
+	if err := uc.checkTransition(ctx, task, dto.StatusID); err != nil {
+		return nil, err
+	}
+
+	previousStatus := task.StatusID
+	err = uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		if err := uc.repository.Move(ctx, task, dto.StatusID, dto.PrevID, dto.NextID, version, userID); err != nil {
+			return err
+		}
+
+		if task.StatusID == entities.StatusCompleted && previousStatus != entities.StatusCompleted {
+			return uc.spawnNext(ctx, userID, task)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetById(ctx, userID, id)
+}
+
+// GetHistory returns a page of the task's activity, newest first.
+func (uc *TaskUsecase) GetHistory(ctx context.Context, userID, id, limit, offset int) (*entities.TaskHistory, error) {
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	events, total, err := uc.repository.GetHistory(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.TaskHistory{Events: events, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// GetSubtasks returns the direct subtasks of a task, or with tree set the
+// whole subtree nested under each child.
+func (uc *TaskUsecase) GetSubtasks(ctx context.Context, userID, id int, tree bool) ([]*entities.Task, error) {
+	task, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireTaskAccess(ctx, uc.projects, task, userID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	descendants, err := uc.repository.GetDescendants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &entities.Task{ID: id}
+	buildTree(root, descendants)
+
+	if !tree {
+		for _, c := range root.Children {
+			c.Children = nil
+		}
+	}
+
+	return root.Children, nil
+}
+
+// checkTransition validates moving the task to the given status: a task
+// cannot start while it is blocked, nor be completed while it has open
+// subtasks if the usecase is configured so.
+func (uc *TaskUsecase) checkTransition(ctx context.Context, current *entities.Task, statusID int) error {
+	if statusID < entities.StatusNew || statusID > entities.StatusCompleted {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown status %d", statusID), nil)
+	}
+
+	if statusID != entities.StatusNew && current.StatusID == entities.StatusNew {
+		if err := uc.checkBlockers(ctx, current.ID); err != nil {
+			return err
+		}
+	}
+
+	if statusID == entities.StatusCompleted && current.StatusID != entities.StatusCompleted && uc.cfg.RequireClosedSubtasks {
+		open, err := uc.repository.CountOpenChildren(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+
+		if open > 0 {
+			return app.NewAppError(app.ErrConflict, fmt.Sprintf("task has %d open subtasks", open), nil)
+		}
+	}
+
+	return nil
+}
+
+// normalizeLabels trims the labels and drops duplicates, rejecting empty
+// or overly long ones. No labels are returned as an empty list, never nil:
+// nil would be written as NULL into the NOT NULL labels column.
+func normalizeLabels(labels []string) ([]string, error) {
+	result := []string{}
+	seen := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || len(l) > maxLabelLength {
+			return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("labels must be between 1 and %d characters", maxLabelLength), nil)
+		}
+		if !seen[l] {
+			seen[l] = true
+			result = append(result, l)
+		}
+	}
+
+	return result, nil
+}
+
+func checkPriority(p string) error {
+	if _, ok := priorityWeight[p]; !ok {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown priority %q", p), nil)
+	}
+
+	return nil
+}
+
+// maxEstimate is the largest estimate the NUMERIC(8, 2) columns hold.
+const maxEstimate = 999999.99
+
+// checkEstimates rejects negative and oversized estimates.
+func checkEstimates(t *entities.Task) error {
+	if err := checkEstimate("estimate_points", t.EstimatePoints); err != nil {
+		return err
+	}
+
+	return checkEstimate("estimate_hours", t.EstimateHours)
+}
+
+func checkEstimate(name string, v *float64) error {
+	if v != nil && !(*v >= 0 && *v <= maxEstimate) {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("%s must be between 0 and %g", name, maxEstimate), nil)
+	}
+
+	return nil
+}
+
+// checkParent rejects parent assignments that would make the task its own
+// ancestor.
+func (uc *TaskUsecase) checkParent(ctx context.Context, userID, id, parentID int) error {
+	if _, err := uc.parentTask(ctx, userID, parentID); err != nil {
+		return err
+	}
+
+	cycle, err := uc.repository.IsAncestor(ctx, id, parentID)
+	if err != nil {
+		return err
+	}
+
+	if cycle {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("task %d cannot be moved under its own subtask %d", id, parentID), nil)
+	}
+
+	return nil
+}
+
+// parentTask returns the task to place a subtask under. The user needs to
+// be able to edit it; parents they cannot see are reported as missing.
+func (uc *TaskUsecase) parentTask(ctx context.Context, userID, parentID int) (*entities.Task, error) {
+	parent, err := uc.repository.GetById(ctx, parentID)
+	if err == nil {
+		err = requireTaskAccess(ctx, uc.projects, parent, userID, entities.RoleEditor)
+	}
+
+	var appErr *app.AppError
+	if errors.As(err, &appErr) && appErr.Type == app.ErrNotFound {
+		return nil, app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("parent task %d does not exist", parentID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+// checkProject validates that a task may be placed in the project by the
+// user: the project must be active and the user at least an editor.
+func (uc *TaskUsecase) checkProject(ctx context.Context, userID int, projectID *int) error {
+	if projectID == nil {
+		if uc.cfg.RequireProject {
+			return app.NewAppError(app.ErrInvalidInput, "project_id is required", nil)
+		}
+		return nil
+	}
+
+	if err := requireRole(ctx, uc.projects, *projectID, userID, entities.RoleEditor); err != nil {
+		return err
+	}
+
+	project, err := uc.projects.GetById(ctx, *projectID)
+	if err != nil {
+		return err
+	}
+
+	if project.Archived {
+		return app.NewAppError(app.ErrConflict, fmt.Sprintf("project %d is archived", *projectID), nil)
+	}
+
 	return nil
 }
 
-func (uc *TaskUsecase) Delete(ctx context.Context, id int) error {
-	err := uc.repository.Delete(ctx, id)
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
 
+// checkTaskAssignee makes sure the assignee of a task exists and, for a
+// project task, is a member of the project, since only members can see
+// project tasks.
+func (uc *TaskUsecase) checkTaskAssignee(ctx context.Context, projectID *int, id int) error {
+	if err := uc.checkAssignee(ctx, id); err != nil {
+		return err
+	}
+
+	if projectID == nil {
+		return nil
+	}
+
+	_, err := uc.projects.GetMemberRole(ctx, *projectID, id)
+	var appErr *app.AppError
+	if errors.As(err, &appErr) && appErr.Type == app.ErrNotFound {
+		return app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("assignee %d is not a member of project %d", id, *projectID))
+	}
+
+	return err
+}
+
+// checkAssignee makes sure the assignee exists in user-service.
+func (uc *TaskUsecase) checkAssignee(ctx context.Context, id int) error {
+	_, err := uc.users.GetUser(ctx, id)
 	if err != nil {
+		var appErr *app.AppError
+		if errors.As(err, &appErr) && appErr.Type == app.ErrNotFound {
+			return app.Wrap(err, app.ErrInvalidInput, fmt.Sprintf("assignee %d does not exist", id))
+		}
 		return err
 	}
 
 	return nil
 }
+
+// buildTree nests descendants (parents first) under root and fills in the
+// progress of every node that has subtasks.
+func buildTree(root *entities.Task, descendants []*entities.Task) {
+	nodes := map[int]*entities.Task{root.ID: root}
+	for _, d := range descendants {
+		nodes[d.ID] = d
+		if d.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*d.ParentID]; ok {
+			parent.Children = append(parent.Children, d)
+		}
+	}
+
+	rollUp(root)
+}
+
+// rollUp returns the number of subtasks below t and how many of them are
+// completed, setting t.Progress along the way.
+func rollUp(t *entities.Task) (total, completed int) {
+	for _, c := range t.Children {
+		ct, cc := rollUp(c)
+		total += ct + 1
+		completed += cc
+		if c.StatusID == entities.StatusCompleted {
+			completed++
+		}
+	}
+
+	if total > 0 {
+		progress := float64(completed) * 100 / float64(total)
+		t.Progress = &progress
+	}
+
+	return total, completed
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+)
+
+// stubTaskRepository records the tasks it is asked to create. Calls to any
+// other repository method panic on the nil embedded interface.
+type stubTaskRepository struct {
+	UserRepository
+	created []*entities.Task
+	series  []*entities.TaskSeries
+	// writes outside of RunInTx
+	untracked int
+}
+
+type stubTxKey struct{}
+
+func (r *stubTaskRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, stubTxKey{}, true))
+}
+
+func (r *stubTaskRepository) track(ctx context.Context) {
+	if ctx.Value(stubTxKey{}) == nil {
+		r.untracked++
+	}
+}
+
+func (r *stubTaskRepository) Create(ctx context.Context, t *entities.Task, actorID int) error {
+	r.track(ctx)
+	t.ID = len(r.created) + 1
+	r.created = append(r.created, t)
+	return nil
+}
+
+func (r *stubTaskRepository) CreateSeries(ctx context.Context, s *entities.TaskSeries) error {
+	r.track(ctx)
+	s.ID = len(r.series) + 1
+	r.series = append(r.series, s)
+	return nil
+}
+
+func (r *stubTaskRepository) GetSeries(ctx context.Context, id int) (*entities.TaskSeries, error) {
+	return r.series[id-1], nil
+}
+
+func (r *stubTaskRepository) OccurrenceExists(ctx context.Context, seriesID, occurrence int) (bool, error) {
+	for _, t := range r.created {
+		if t.SeriesID != nil && *t.SeriesID == seriesID && t.Occurrence == occurrence {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+
+func TestCreateWithoutLabels(t *testing.T) {
+	repo := &stubTaskRepository{}
+	uc := NewTaskUsecase(repo, nil, nil, nopLogger{}, TaskUsecaseConfig{})
+
+	task, err := uc.Create(context.Background(), &entities.CreateTaskDto{UserID: 1, Title: "No labels"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d tasks, want 1", len(repo.created))
+	}
+	// labels is NOT NULL: a nil slice would be written as NULL.
+	if task.Labels == nil {
+		t.Fatal("labels are nil, want an empty list")
+	}
+	if len(task.Labels) != 0 {
+		t.Fatalf("labels = %v, want none", task.Labels)
+	}
+}
+
+func TestCreateRecurringInOneTransaction(t *testing.T) {
+	repo := &stubTaskRepository{}
+	uc := NewTaskUsecase(repo, nil, nil, nopLogger{}, TaskUsecaseConfig{})
+
+	rule := "FREQ=WEEKLY"
+	task, err := uc.Create(context.Background(), &entities.CreateTaskDto{
+		UserID:   1,
+		Title:    "Weekly report",
+		Deadline: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		RRule:    &rule,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if len(repo.series) != 1 || task.SeriesID == nil || *task.SeriesID != repo.series[0].ID {
+		t.Fatalf("task series = %v, want the created series", task.SeriesID)
+	}
+	if repo.untracked != 0 {
+		t.Fatalf("%d writes ran outside the transaction", repo.untracked)
+	}
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	for _, labels := range [][]string{nil, {}} {
+		got, err := normalizeLabels(labels)
+		if err != nil {
+			t.Fatalf("normalizeLabels(%v): %v", labels, err)
+		}
+		if got == nil || len(got) != 0 {
+			t.Fatalf("normalizeLabels(%v) = %#v, want an empty list", labels, got)
+		}
+	}
+
+	got, err := normalizeLabels([]string{" bug ", "bug", "ui"})
+	if err != nil {
+		t.Fatalf("normalizeLabels: %v", err)
+	}
+	if len(got) != 2 || got[0] != "bug" || got[1] != "ui" {
+		t.Fatalf("normalizeLabels = %v, want [bug ui]", got)
+	}
+
+	if _, err := normalizeLabels([]string{" "}); err == nil {
+		t.Fatal("normalizeLabels accepted a blank label")
+	}
+}
+
+type stubUsers struct{}
+
+func (stubUsers) GetUser(ctx context.Context, id int) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+// stubProjects knows the members of project 1.
+type stubProjects struct {
+	ProjectAccess
+	members map[int]string
+}
+
+func (p stubProjects) GetMemberRole(ctx context.Context, projectID, userID int) (string, error) {
+	if role, ok := p.members[userID]; ok && projectID == 1 {
+		return role, nil
+	}
+
+	return "", app.NewAppError(app.ErrNotFound, "member not found", nil)
+}
+
+func TestCheckTaskAssignee(t *testing.T) {
+	projects := stubProjects{members: map[int]string{2: entities.RoleViewer}}
+	uc := NewTaskUsecase(&stubTaskRepository{}, projects, stubUsers{}, nopLogger{}, TaskUsecaseConfig{})
+
+	project := 1
+	if err := uc.checkTaskAssignee(context.Background(), &project, 2); err != nil {
+		t.Fatalf("member: %v", err)
+	}
+	if err := uc.checkTaskAssignee(context.Background(), nil, 3); err != nil {
+		t.Fatalf("task without a project: %v", err)
+	}
+
+	err := uc.checkTaskAssignee(context.Background(), &project, 3)
+	var appErr *app.AppError
+	if !errors.As(err, &appErr) || appErr.Type != app.ErrInvalidInput {
+		t.Fatalf("non-member: err = %v, want invalid input", err)
+	}
+}
@@ -0,0 +1,400 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+const (
+	// maxTemplateNameLength is the length of templates.name.
+	maxTemplateNameLength  = 100
+	maxTemplateTasks       = 100
+	maxTemplateDepth       = 5
+	maxChecklistItems      = 100
+	maxChecklistItemLength = 200
+)
+
+var (
+	templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	relativeDeadline    = regexp.MustCompile(`^\+([0-9]{1,6})([hdw])$`)
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t *entities.Template) error
+	GetById(ctx context.Context, id int) (*entities.Template, error)
+	GetAllForUser(ctx context.Context, userID int) ([]*entities.Template, error)
+	Update(ctx context.Context, t *entities.Template) error
+	Delete(ctx context.Context, id int) error
+}
+
+type TaskTreeCreator interface {
+	CreateTree(ctx context.Context, tree *entities.CreateTaskTreeDto) (*entities.Task, error)
+}
+
+// TemplateUsecase manages task templates. As with views, templates are
+// changed by their owner only and sharing one with a project takes the
+// editor role there.
+type TemplateUsecase struct {
+	repository TemplateRepository
+	tasks      TaskTreeCreator
+	projects   ProjectAccess
+	logger     logger.ILogger
+}
+
+func NewTemplateUsecase(r TemplateRepository, t TaskTreeCreator, p ProjectAccess, l logger.ILogger) *TemplateUsecase {
+	return &TemplateUsecase{
+		repository: r,
+		tasks:      t,
+		projects:   p,
+		logger:     l,
+	}
+}
+
+func (uc *TemplateUsecase) GetAll(ctx context.Context, userID int) ([]*entities.Template, error) {
+	return uc.repository.GetAllForUser(ctx, userID)
+}
+
+// Get returns a template of the user or one shared with them.
+func (uc *TemplateUsecase) Get(ctx context.Context, userID, id int) (*entities.Template, error) {
+	t, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.UserID == userID {
+		return t, nil
+	}
+
+	if t.ProjectID != nil {
+		err := requireRole(ctx, uc.projects, *t.ProjectID, userID, entities.RoleViewer)
+		if err == nil {
+			return t, nil
+		}
+		var appErr *app.AppError
+		if !errors.As(err, &appErr) || appErr.Type != app.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("template %d not found", id), nil)
+}
+
+func (uc *TemplateUsecase) Create(ctx context.Context, userID int, dto *entities.TemplateDto) (*entities.Template, error) {
+	t := &entities.Template{UserID: userID}
+	if err := uc.apply(ctx, t, dto); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repository.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (uc *TemplateUsecase) Update(ctx context.Context, userID, id int, dto *entities.TemplateDto) (*entities.Template, error) {
+	t, err := uc.own(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.apply(ctx, t, dto); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repository.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (uc *TemplateUsecase) Delete(ctx context.Context, userID, id int) error {
+	if _, err := uc.own(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return uc.repository.Delete(ctx, id)
+}
+
+// Instantiate creates the template's task tree for the user in one
+// transaction, with the placeholders filled in from the variables and the
+// deadlines counted from dto.Start. Every placeholder needs a variable.
+// Tasks have no checklist of their own, so checklist items are appended
+// to the description as a Markdown task list.
+func (uc *TemplateUsecase) Instantiate(ctx context.Context, userID, id int, dto *entities.InstantiateTemplateDto) (*entities.Task, error) {
+	t, err := uc.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range placeholders(&t.TemplateTask) {
+		if _, ok := dto.Variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, app.NewAppError(app.ErrInvalidInput, "missing template variables: "+strings.Join(missing, ", "), nil)
+	}
+
+	start := time.Now()
+	if dto.Start != nil {
+		start = *dto.Start
+	}
+
+	tree, err := instantiate(&t.TemplateTask, dto.Variables, start)
+	if err != nil {
+		return nil, err
+	}
+
+	tree.ProjectID = t.ProjectID
+	setTreeUser(tree, userID)
+
+	task, err := uc.tasks.CreateTree(ctx, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Instantiated template", "template_id", id, "task_id", task.ID, "user_id", userID)
+
+	return task, nil
+}
+
+// own returns a template the user owns; templates shared with them are
+// read-only.
+func (uc *TemplateUsecase) own(ctx context.Context, userID, id int) (*entities.Template, error) {
+	t, err := uc.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.UserID != userID {
+		return nil, app.NewAppError(app.ErrUnauthorized, fmt.Sprintf("template %d belongs to another user", id), nil)
+	}
+
+	return t, nil
+}
+
+// apply validates dto and copies it onto t.
+func (uc *TemplateUsecase) apply(ctx context.Context, t *entities.Template, dto *entities.TemplateDto) error {
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		return app.NewAppError(app.ErrInvalidInput, "name is required", nil)
+	}
+	if utf8.RuneCountInString(name) > maxTemplateNameLength {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("name is limited to %d characters", maxTemplateNameLength), nil)
+	}
+
+	count := 0
+	if err := checkTemplateTask(&dto.TemplateTask, 1, &count); err != nil {
+		return err
+	}
+
+	if dto.ProjectID != nil && !sameID(dto.ProjectID, t.ProjectID) {
+		if err := requireRole(ctx, uc.projects, *dto.ProjectID, t.UserID, entities.RoleEditor); err != nil {
+			return err
+		}
+	}
+
+	t.Name, t.ProjectID, t.TemplateTask = name, dto.ProjectID, dto.TemplateTask
+
+	return nil
+}
+
+// checkTemplateTask validates the task and its subtasks at the given depth
+// and normalizes them in place; count tallies the tasks of the template.
+func checkTemplateTask(t *entities.TemplateTask, depth int, count *int) error {
+	*count++
+	if *count > maxTemplateTasks {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("templates are limited to %d tasks", maxTemplateTasks), nil)
+	}
+	if depth > maxTemplateDepth {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("subtasks can be nested at most %d levels deep", maxTemplateDepth-1), nil)
+	}
+
+	t.Title = strings.TrimSpace(t.Title)
+	if t.Title == "" {
+		return app.NewAppError(app.ErrInvalidInput, "title is required", nil)
+	}
+
+	if t.Priority != "" {
+		if err := checkPriority(t.Priority); err != nil {
+			return err
+		}
+	}
+
+	labels, err := normalizeLabels(t.Labels)
+	if err != nil {
+		return err
+	}
+	t.Labels = labels
+
+	if len(t.Checklist) > maxChecklistItems {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("checklists are limited to %d items", maxChecklistItems), nil)
+	}
+	for i, item := range t.Checklist {
+		item = strings.TrimSpace(item)
+		if item == "" || utf8.RuneCountInString(item) > maxChecklistItemLength {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("checklist items must be between 1 and %d characters", maxChecklistItemLength), nil)
+		}
+		t.Checklist[i] = item
+	}
+
+	if _, err := deadlineFrom(t.Deadline, time.Now()); err != nil {
+		return err
+	}
+
+	for _, sub := range t.Subtasks {
+		if sub == nil {
+			return app.NewAppError(app.ErrInvalidInput, "subtasks cannot be null", nil)
+		}
+		if err := checkTemplateTask(sub, depth+1, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deadlineFrom resolves a relative deadline against start. No deadline
+// gives the zero time.
+func deadlineFrom(deadline string, start time.Time) (time.Time, error) {
+	if deadline == "" {
+		return time.Time{}, nil
+	}
+
+	m := relativeDeadline.FindStringSubmatch(deadline)
+	if m == nil {
+		return time.Time{}, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("invalid deadline %q, expected +<n>h, +<n>d or +<n>w", deadline), nil)
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	var t time.Time
+	switch m[2] {
+	case "h":
+		t = start.Add(time.Duration(n) * time.Hour)
+	case "d":
+		t = start.AddDate(0, 0, n)
+	case "w":
+		t = start.AddDate(0, 0, 7*n)
+	}
+
+	if t.After(start.AddDate(10, 0, 0)) {
+		return time.Time{}, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("deadline %q is more than 10 years ahead", deadline), nil)
+	}
+
+	return t, nil
+}
+
+// placeholders returns the sorted names of the placeholders used in the
+// task and its subtasks.
+func placeholders(t *entities.TemplateTask) []string {
+	seen := map[string]bool{}
+	var walk func(t *entities.TemplateTask)
+	walk = func(t *entities.TemplateTask) {
+		texts := append([]string{t.Title, t.Description}, t.Labels...)
+		for _, s := range append(texts, t.Checklist...) {
+			for _, m := range templatePlaceholder.FindAllStringSubmatch(s, -1) {
+				seen[m[1]] = true
+			}
+		}
+		for _, sub := range t.Subtasks {
+			walk(sub)
+		}
+	}
+	walk(t)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// instantiate builds the tasks to create from the template task.
+func instantiate(t *entities.TemplateTask, vars map[string]string, start time.Time) (*entities.CreateTaskTreeDto, error) {
+	fill := func(s string) string {
+		return templatePlaceholder.ReplaceAllStringFunc(s, func(p string) string {
+			return vars[templatePlaceholder.FindStringSubmatch(p)[1]]
+		})
+	}
+
+	deadline, err := deadlineFrom(t.Deadline, start)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, len(t.Labels))
+	for i, l := range t.Labels {
+		labels[i] = fill(l)
+	}
+
+	checklist := make([]string, len(t.Checklist))
+	for i, item := range t.Checklist {
+		checklist[i] = fill(item)
+	}
+
+	tree := &entities.CreateTaskTreeDto{
+		CreateTaskDto: entities.CreateTaskDto{
+			Title:       fill(t.Title),
+			Description: withChecklist(fill(t.Description), checklist),
+			Deadline:    deadline,
+			Priority:    t.Priority,
+			Labels:      labels,
+		},
+	}
+
+	for _, sub := range t.Subtasks {
+		child, err := instantiate(sub, vars, start)
+		if err != nil {
+			return nil, err
+		}
+		tree.Subtasks = append(tree.Subtasks, child)
+	}
+
+	return tree, nil
+}
+
+// withChecklist appends the items to the description as a Markdown task
+// list.
+func withChecklist(description string, items []string) string {
+	if len(items) == 0 {
+		return description
+	}
+
+	var b strings.Builder
+	b.WriteString(description)
+	if description != "" {
+		b.WriteString("\n\n")
+	}
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- [ ] ")
+		b.WriteString(item)
+	}
+
+	return b.String()
+}
+
+func setTreeUser(tree *entities.CreateTaskTreeDto, userID int) {
+	tree.UserID = userID
+	for _, sub := range tree.Subtasks {
+		setTreeUser(sub, userID)
+	}
+}
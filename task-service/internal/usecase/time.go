@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// maxTimeNoteLength bounds the note of a time entry.
+const maxTimeNoteLength = 1000
+
+type TimeRepository interface {
+	GetAllByTaskId(ctx context.Context, taskID int) ([]*entities.TimeEntry, error)
+	GetById(ctx context.Context, taskID int, id int64) (*entities.TimeEntry, error)
+	GetRunning(ctx context.Context, userID int) (*entities.TimeEntry, error)
+	Create(ctx context.Context, e *entities.TimeEntry) error
+	Stop(ctx context.Context, userID, taskID int, at time.Time) (*entities.TimeEntry, error)
+	Update(ctx context.Context, e *entities.TimeEntry) error
+	Delete(ctx context.Context, taskID int, id int64) error
+	Report(ctx context.Context, f entities.TimeReportFilter) ([]*entities.TimeReportGroup, int64, error)
+}
+
+// TimeUsecase tracks the time users spend on tasks, by timer or entered
+// by hand. Everybody who can edit a task may track time on it; entries
+// can only be changed by the user they belong to.
+type TimeUsecase struct {
+	repository TimeRepository
+	tasks      TaskGetter
+	projects   ProjectAccess
+	logger     logger.ILogger
+}
+
+func NewTimeUsecase(r TimeRepository, t TaskGetter, p ProjectAccess, l logger.ILogger) *TimeUsecase {
+	return &TimeUsecase{
+		repository: r,
+		tasks:      t,
+		projects:   p,
+		logger:     l,
+	}
+}
+
+func (uc *TimeUsecase) GetAll(ctx context.Context, userID, taskID int) ([]*entities.TimeEntry, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.repository.GetAllByTaskId(ctx, taskID)
+}
+
+// GetRunning returns the user's running timer, nil if there is none.
+func (uc *TimeUsecase) GetRunning(ctx context.Context, userID int) (*entities.TimeEntry, error) {
+	return uc.repository.GetRunning(ctx, userID)
+}
+
+// Start starts a timer on the task. A user has one timer at most, so
+// starting another while one is running is a conflict.
+func (uc *TimeUsecase) Start(ctx context.Context, userID, taskID int, note string) (*entities.TimeEntry, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := validateTimeNote(note); err != nil {
+		return nil, err
+	}
+
+	entry := &entities.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      note,
+	}
+	if err := uc.repository.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Debug("Timer started", "user_id", userID, "task_id", taskID, "entry_id", entry.ID)
+	return entry, nil
+}
+
+// Stop stops the user's timer on the task. It only needs the task to be
+// visible, so that losing edit access does not leave a timer running.
+func (uc *TimeUsecase) Stop(ctx context.Context, userID, taskID int) (*entities.TimeEntry, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	entry, err := uc.repository.Stop(ctx, userID, taskID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Debug("Timer stopped", "user_id", userID, "task_id", taskID, "entry_id", entry.ID)
+	return entry, nil
+}
+
+// Create records time spent on the task without a timer.
+func (uc *TimeUsecase) Create(ctx context.Context, userID, taskID int, dto entities.TimeEntryDto) (*entities.TimeEntry, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := validateTimeEntry(dto, false); err != nil {
+		return nil, err
+	}
+
+	entry := &entities.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: dto.StartedAt,
+		EndedAt:   &dto.EndedAt,
+		Note:      dto.Note,
+	}
+	if err := uc.repository.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Update replaces the times and note of one of the user's entries. A
+// running timer keeps running when no end is given.
+func (uc *TimeUsecase) Update(ctx context.Context, userID, taskID int, id int64, dto entities.TimeEntryDto) (*entities.TimeEntry, error) {
+	entry, err := uc.ownEntry(ctx, userID, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	running := entry.EndedAt == nil
+	if err := validateTimeEntry(dto, running); err != nil {
+		return nil, err
+	}
+
+	entry.StartedAt = dto.StartedAt
+	entry.Note = dto.Note
+	entry.EndedAt = nil
+	if !dto.EndedAt.IsZero() {
+		entry.EndedAt = &dto.EndedAt
+	}
+
+	if err := uc.repository.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (uc *TimeUsecase) Delete(ctx context.Context, userID, taskID int, id int64) error {
+	if _, err := uc.ownEntry(ctx, userID, taskID, id); err != nil {
+		return err
+	}
+
+	return uc.repository.Delete(ctx, taskID, id)
+}
+
+// Report sums up the user's time in [f.From, f.To), grouped by day (the
+// default), task or label.
+func (uc *TimeUsecase) Report(ctx context.Context, f entities.TimeReportFilter) (*entities.TimeReport, error) {
+	if f.GroupBy == "" {
+		f.GroupBy = entities.GroupByDay
+	}
+
+	switch f.GroupBy {
+	case entities.GroupByDay, entities.GroupByTask, entities.GroupByLabel:
+	default:
+		return nil, app.NewAppError(app.ErrInvalidInput, "group_by must be day, task or label", nil)
+	}
+
+	if f.From.IsZero() || f.To.IsZero() {
+		return nil, app.NewAppError(app.ErrInvalidInput, "from and to are required", nil)
+	}
+	if !f.To.After(f.From) {
+		return nil, app.NewAppError(app.ErrInvalidInput, "to must be after from", nil)
+	}
+
+	groups, total, err := uc.repository.Report(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.TimeReport{
+		From:    f.From,
+		To:      f.To,
+		GroupBy: f.GroupBy,
+		Total:   total,
+		Groups:  groups,
+	}, nil
+}
+
+// ownEntry returns an entry of the user on a task they can still edit.
+func (uc *TimeUsecase) ownEntry(ctx context.Context, userID, taskID int, id int64) (*entities.TimeEntry, error) {
+	if err := uc.checkTask(ctx, userID, taskID, entities.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	entry, err := uc.repository.GetById(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.UserID != userID {
+		return nil, app.NewAppError(app.ErrUnauthorized, fmt.Sprintf("time entry %d belongs to another user", id), nil)
+	}
+
+	return entry, nil
+}
+
+func (uc *TimeUsecase) checkTask(ctx context.Context, userID, taskID int, min string) error {
+	task, err := uc.tasks.GetById(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return requireTaskAccess(ctx, uc.projects, task, userID, min)
+}
+
+// validateTimeEntry checks the times of an entry; running entries may
+// leave out the end.
+func validateTimeEntry(dto entities.TimeEntryDto, running bool) error {
+	if dto.StartedAt.IsZero() {
+		return app.NewAppError(app.ErrInvalidInput, "started_at is required", nil)
+	}
+	if dto.StartedAt.After(time.Now()) {
+		return app.NewAppError(app.ErrInvalidInput, "started_at cannot be in the future", nil)
+	}
+
+	if dto.EndedAt.IsZero() {
+		if !running {
+			return app.NewAppError(app.ErrInvalidInput, "ended_at is required", nil)
+		}
+	} else {
+		if dto.EndedAt.Before(dto.StartedAt) {
+			return app.NewAppError(app.ErrInvalidInput, "ended_at cannot be before started_at", nil)
+		}
+		if dto.EndedAt.After(time.Now()) {
+			return app.NewAppError(app.ErrInvalidInput, "ended_at cannot be in the future", nil)
+		}
+	}
+
+	return validateTimeNote(dto.Note)
+}
+
+func validateTimeNote(note string) error {
+	if len(note) > maxTimeNoteLength {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("note is limited to %d bytes", maxTimeNoteLength), nil)
+	}
+
+	return nil
+}
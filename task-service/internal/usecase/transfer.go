@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/transfer"
+)
+
+// errImportRollback undoes an import that failed or was only a dry run.
+var errImportRollback = errors.New("import rolled back")
+
+// Export calls fn for every task matching the filter, in board order.
+func (uc *TaskUsecase) Export(ctx context.Context, f entities.TaskFilter, fn func(t *entities.Task) error) error {
+	if f.ProjectID != nil {
+		if err := requireRole(ctx, uc.projects, *f.ProjectID, f.UserID, entities.RoleViewer); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.resolveFieldFilter(ctx, &f); err != nil {
+		return err
+	}
+
+	return uc.repository.Each(ctx, f, fn)
+}
+
+// Import reads tasks in the given format and creates them with the same
+// validation as Create, all within one transaction. mapping renames source
+// columns to task fields. The import is committed only when every row
+// succeeds and dryRun is not set; the report lists the failed rows either
+// way.
+func (uc *TaskUsecase) Import(ctx context.Context, userID int, r io.Reader, format string, mapping map[string]string, dryRun bool) (*entities.ImportReport, error) {
+	if err := transfer.CheckMapping(mapping); err != nil {
+		return nil, app.Wrap(err, app.ErrInvalidInput, err.Error())
+	}
+
+	rows, err := transfer.Decode(r, format, mapping, uc.cfg.MaxImportRows)
+	if errors.Is(err, transfer.ErrTooManyRows) {
+		return nil, app.NewAppError(app.ErrTooLarge, fmt.Sprintf("imports are limited to %d rows", uc.cfg.MaxImportRows), err)
+	}
+	if err != nil {
+		return nil, app.Wrap(err, app.ErrInvalidInput, err.Error())
+	}
+
+	report := &entities.ImportReport{
+		DryRun:  dryRun,
+		Total:   len(rows),
+		Created: []int{},
+		Errors:  []entities.ImportError{},
+	}
+
+	err = uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+		for _, row := range rows {
+			if row.Error != "" {
+				report.Errors = append(report.Errors, entities.ImportError{Row: row.Row, Error: row.Error, Code: "parse_error"})
+				continue
+			}
+
+			var id int
+			err := uc.repository.RunInTx(ctx, func(ctx context.Context) error {
+				var err error
+				id, err = uc.importRow(ctx, userID, row)
+				return err
+			})
+			if err != nil {
+				code, message := uc.bulkError(err, row.Row)
+				report.Errors = append(report.Errors, entities.ImportError{Row: row.Row, Error: message, Code: code})
+				continue
+			}
+
+			report.Valid++
+			report.Created = append(report.Created, id)
+		}
+
+		if dryRun || len(report.Errors) > 0 {
+			return errImportRollback
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errImportRollback) {
+		report.Created = []int{}
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Committed = true
+	return report, nil
+}
+
+func (uc *TaskUsecase) importRow(ctx context.Context, userID int, row *entities.ImportRow) (int, error) {
+	dto := row.Task
+	dto.UserID = userID
+
+	task, err := uc.Create(ctx, &dto)
+	if err != nil {
+		return 0, err
+	}
+
+	if row.StatusID != 0 && row.StatusID != task.StatusID {
+		fields := map[string]json.RawMessage{"status_id": json.RawMessage(fmt.Sprint(row.StatusID))}
+		if _, err := uc.Patch(ctx, userID, task.ID, 0, &entities.TaskPatch{Fields: fields}, ""); err != nil {
+			return 0, err
+		}
+	}
+
+	return task.ID, nil
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+)
+
+// deadlineHorizon is how far ahead a deadline starts to make a task urgent.
+const deadlineHorizon = 7 * 24 * time.Hour
+
+var priorityWeight = map[string]float64{
+	entities.PriorityLow:    0,
+	entities.PriorityNormal: 1.0 / 3,
+	entities.PriorityHigh:   2.0 / 3,
+	entities.PriorityUrgent: 1,
+}
+
+// urgency scores a task from 0 to 100, weighing its importance (priority)
+// and its urgency (how close the deadline is) equally, Eisenhower style.
+// Completed tasks always score 0.
+func urgency(t *entities.Task, now time.Time) float64 {
+	if t.StatusID == entities.StatusCompleted {
+		return 0
+	}
+
+	importance := priorityWeight[t.Priority]
+
+	closeness := 0.0
+	if !t.Deadline.IsZero() {
+		left := t.Deadline.Sub(now)
+		switch {
+		case left <= 0:
+			closeness = 1
+		case left < deadlineHorizon:
+			closeness = 1 - float64(left)/float64(deadlineHorizon)
+		}
+	}
+
+	return math.Round((importance+closeness)*50*100) / 100
+}
+
+// sortByUrgency fills in the urgency of each task and orders them from the
+// most to the least urgent, earlier deadlines first on ties.
+func sortByUrgency(tasks []*entities.Task, now time.Time) {
+	for _, t := range tasks {
+		score := urgency(t, now)
+		t.Urgency = &score
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if *tasks[i].Urgency != *tasks[j].Urgency {
+			return *tasks[i].Urgency > *tasks[j].Urgency
+		}
+		return effectiveDeadline(tasks[i]).Before(effectiveDeadline(tasks[j]))
+	})
+}
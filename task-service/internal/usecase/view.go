@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+// maxViewNameLength is the length of views.name.
+const maxViewNameLength = 100
+
+type ViewRepository interface {
+	Create(ctx context.Context, v *entities.View) error
+	GetById(ctx context.Context, id int) (*entities.View, error)
+	GetAllForUser(ctx context.Context, userID int) ([]*entities.View, error)
+	Update(ctx context.Context, v *entities.View) error
+	Delete(ctx context.Context, id int) error
+}
+
+type TaskLister interface {
+	List(ctx context.Context, f entities.TaskFilter) ([]*entities.Task, error)
+}
+
+// ViewUsecase manages saved views. Views are changed by their owner only;
+// sharing a view with a project takes the editor role there.
+type ViewUsecase struct {
+	repository ViewRepository
+	tasks      TaskLister
+	projects   ProjectAccess
+	logger     logger.ILogger
+}
+
+func NewViewUsecase(r ViewRepository, t TaskLister, p ProjectAccess, l logger.ILogger) *ViewUsecase {
+	return &ViewUsecase{
+		repository: r,
+		tasks:      t,
+		projects:   p,
+		logger:     l,
+	}
+}
+
+func (uc *ViewUsecase) GetAll(ctx context.Context, userID int) ([]*entities.View, error) {
+	return uc.repository.GetAllForUser(ctx, userID)
+}
+
+// Get returns a view of the user or one shared with them.
+func (uc *ViewUsecase) Get(ctx context.Context, userID, id int) (*entities.View, error) {
+	v, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.UserID == userID {
+		return v, nil
+	}
+
+	if v.ProjectID != nil {
+		err := requireRole(ctx, uc.projects, *v.ProjectID, userID, entities.RoleViewer)
+		if err == nil {
+			return v, nil
+		}
+		var appErr *app.AppError
+		if !errors.As(err, &appErr) || appErr.Type != app.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("view %d not found", id), nil)
+}
+
+func (uc *ViewUsecase) Create(ctx context.Context, userID int, dto *entities.ViewDto) (*entities.View, error) {
+	v := &entities.View{UserID: userID}
+	if err := uc.apply(ctx, v, dto); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repository.Create(ctx, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (uc *ViewUsecase) Update(ctx context.Context, userID, id int, dto *entities.ViewDto) (*entities.View, error) {
+	v, err := uc.own(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.apply(ctx, v, dto); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repository.Update(ctx, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (uc *ViewUsecase) Delete(ctx context.Context, userID, id int) error {
+	if _, err := uc.own(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return uc.repository.Delete(ctx, id)
+}
+
+// Tasks runs the view for the user.
+func (uc *ViewUsecase) Tasks(ctx context.Context, userID, id int) ([]*entities.Task, error) {
+	v, err := uc.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := viewFilter(v, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.tasks.List(ctx, filter)
+}
+
+// own returns a view the user owns; views shared with them are read-only.
+func (uc *ViewUsecase) own(ctx context.Context, userID, id int) (*entities.View, error) {
+	v, err := uc.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.UserID != userID {
+		return nil, app.NewAppError(app.ErrUnauthorized, fmt.Sprintf("view %d belongs to another user", id), nil)
+	}
+
+	return v, nil
+}
+
+// apply validates dto and copies it onto v.
+func (uc *ViewUsecase) apply(ctx context.Context, v *entities.View, dto *entities.ViewDto) error {
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		return app.NewAppError(app.ErrInvalidInput, "name is required", nil)
+	}
+	if utf8.RuneCountInString(name) > maxViewNameLength {
+		return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("name is limited to %d characters", maxViewNameLength), nil)
+	}
+
+	if dto.ProjectID != nil && !sameID(dto.ProjectID, v.ProjectID) {
+		if err := requireRole(ctx, uc.projects, *dto.ProjectID, v.UserID, entities.RoleEditor); err != nil {
+			return err
+		}
+	}
+
+	filter := dto.Filter
+	if filter == nil {
+		filter = map[string]string{}
+	}
+
+	v.Name, v.ProjectID, v.Filter, v.Sort = name, dto.ProjectID, filter, dto.Sort
+
+	_, err := viewFilter(v, v.UserID)
+	return err
+}
+
+// viewFilter builds the task filter of the view for the user, checking it
+// against the grammar of GET /tasks.
+func viewFilter(v *entities.View, userID int) (entities.TaskFilter, error) {
+	values := url.Values{}
+	for name, value := range v.Filter {
+		if !entities.IsTaskFilterParam(name) {
+			return entities.TaskFilter{}, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown filter %q", name), nil)
+		}
+		values.Set(name, value)
+	}
+
+	filter, err := entities.ParseTaskFilter(values, userID)
+	if err != nil {
+		return filter, app.Wrap(err, app.ErrInvalidInput, err.Error())
+	}
+
+	if filter.Sort, err = entities.ParseTaskSort(v.Sort); err != nil {
+		return filter, app.Wrap(err, app.ErrInvalidInput, err.Error())
+	}
+
+	if v.ProjectID != nil {
+		if filter.ProjectID != nil && *filter.ProjectID != *v.ProjectID {
+			return filter, app.NewAppError(app.ErrInvalidInput, "the project_id filter must match the view's project", nil)
+		}
+		filter.ProjectID = v.ProjectID
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dielit66/task-management-system/internal/entities"
+	app "github.com/dielit66/task-management-system/internal/errors"
+	"github.com/dielit66/task-management-system/internal/logger"
+)
+
+const (
+	// webhookSecretBytes is the size of generated signing secrets.
+	webhookSecretBytes = 32
+	// maxWebhookErrorLength bounds the error text kept per delivery.
+	maxWebhookErrorLength = 1000
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, w *entities.Webhook) error
+	GetById(ctx context.Context, id int) (*entities.Webhook, error)
+	GetAllByUserId(ctx context.Context, userID int) ([]*entities.Webhook, error)
+	Update(ctx context.Context, w *entities.Webhook) error
+	Delete(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, webhookID int, status string, limit, offset int) ([]*entities.WebhookDelivery, int, error)
+	CreateDelivery(ctx context.Context, d *entities.WebhookDelivery) error
+	SaveDelivery(ctx context.Context, d *entities.WebhookDelivery) error
+	Redeliver(ctx context.Context, webhookID int, id int64) (*entities.WebhookDelivery, error)
+	ProcessDue(ctx context.Context, limit int, lease time.Duration, deliver func(ctx context.Context, w *entities.Webhook, d *entities.WebhookDelivery)) (int, error)
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+type WebhookUsecaseConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	Timeout   time.Duration
+	// MaxAttempts is how often a delivery is tried before it is dead.
+	MaxAttempts int
+	// Retries back off exponentially from MinBackoff up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Retention is how long successful deliveries stay in the log.
+	Retention time.Duration
+	// AllowedNetworks are CIDRs webhooks may target even though they are
+	// loopback, private or link-local addresses, e.g. for tests.
+	AllowedNetworks []string
+}
+
+// WebhookUsecase manages webhook subscriptions and delivers the events
+// queued for them. Every request carries these headers:
+//
+//	X-Webhook-Event      the event type
+//	X-Webhook-Delivery   the delivery id, stable across retries
+//	X-Webhook-Timestamp  Unix time of the attempt
+//	X-Webhook-Signature  "sha256=" and the hex HMAC-SHA256 of the
+//	                     timestamp, a ".", and the body, keyed by the secret
+type WebhookUsecase struct {
+	repository WebhookRepository
+	targets    *targetPolicy
+	http       *http.Client
+	logger     logger.ILogger
+	cfg        WebhookUsecaseConfig
+}
+
+func NewWebhookUsecase(r WebhookRepository, l logger.ILogger, cfg WebhookUsecaseConfig) (*WebhookUsecase, error) {
+	targets, err := newTargetPolicy(cfg.AllowedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookUsecase{
+		repository: r,
+		targets:    targets,
+		http:       targets.client(cfg.Timeout),
+		logger:     l,
+		cfg:        cfg,
+	}, nil
+}
+
+func (uc *WebhookUsecase) GetAll(ctx context.Context, userID int) ([]*entities.Webhook, error) {
+	return uc.repository.GetAllByUserId(ctx, userID)
+}
+
+// Get returns the webhook if it belongs to the user. Other users' webhooks
+// are reported as missing.
+func (uc *WebhookUsecase) Get(ctx context.Context, userID, id int) (*entities.Webhook, error) {
+	w, err := uc.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.UserID != userID {
+		return nil, app.NewAppError(app.ErrNotFound, fmt.Sprintf("webhook %d not found", id), nil)
+	}
+
+	return w, nil
+}
+
+// Create subscribes a webhook, generating its secret when none is given.
+func (uc *WebhookUsecase) Create(ctx context.Context, w *entities.Webhook) error {
+	if err := uc.validate(w); err != nil {
+		return err
+	}
+
+	if w.Secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		w.Secret = hex.EncodeToString(buf)
+	}
+
+	if err := uc.repository.Create(ctx, w); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Webhook created", "webhook_id", w.ID, "user_id", w.UserID, "events", []string(w.Events))
+	return nil
+}
+
+// Update replaces the webhook settings. An empty secret keeps the current
+// one.
+func (uc *WebhookUsecase) Update(ctx context.Context, userID int, w *entities.Webhook) error {
+	current, err := uc.Get(ctx, userID, w.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.validate(w); err != nil {
+		return err
+	}
+
+	if w.Secret == "" {
+		w.Secret = current.Secret
+	}
+	w.UserID = current.UserID
+	w.CreatedAt = current.CreatedAt
+
+	return uc.repository.Update(ctx, w)
+}
+
+func (uc *WebhookUsecase) Delete(ctx context.Context, userID, id int) error {
+	if _, err := uc.Get(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return uc.repository.Delete(ctx, id)
+}
+
+func (uc *WebhookUsecase) validate(w *entities.Webhook) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return app.NewAppError(app.ErrInvalidInput, "url must be an absolute http or https URL", err)
+	}
+
+	if err := uc.targets.checkHost(u.Hostname()); err != nil {
+		return app.NewAppError(app.ErrInvalidInput, "url must not point into a private network", err)
+	}
+
+	if len(w.Events) == 0 {
+		return app.NewAppError(app.ErrInvalidInput, "at least one event type is required", nil)
+	}
+
+	seen := map[string]bool{}
+	events := w.Events[:0]
+	for _, e := range w.Events {
+		if !slices.Contains(entities.WebhookEvents, e) {
+			return app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown event type %q", e), nil)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+	w.Events = events
+
+	return nil
+}
+
+func (uc *WebhookUsecase) GetDeliveries(ctx context.Context, userID, id int, status string, limit, offset int) (*entities.WebhookDeliveries, error) {
+	if _, err := uc.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "", entities.DeliveryPending, entities.DeliveryDelivered, entities.DeliveryDead:
+	default:
+		return nil, app.NewAppError(app.ErrInvalidInput, fmt.Sprintf("unknown delivery status %q", status), nil)
+	}
+
+	deliveries, total, err := uc.repository.GetDeliveries(ctx, id, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.WebhookDeliveries{Deliveries: deliveries, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// Redeliver queues a delivery again, typically a dead one once the
+// receiver has been fixed.
+func (uc *WebhookUsecase) Redeliver(ctx context.Context, userID, id int, deliveryID int64) (*entities.WebhookDelivery, error) {
+	if _, err := uc.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return uc.repository.Redeliver(ctx, id, deliveryID)
+}
+
+// Test sends a test event right away and logs it like any other delivery.
+// A failed test is retried like any other delivery as well.
+func (uc *WebhookUsecase) Test(ctx context.Context, userID, id int) (*entities.WebhookDelivery, error) {
+	w, err := uc.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":       entities.EventWebhookTest,
+		"created_at": time.Now().UTC(),
+		"webhook_id": w.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The first attempt is made here, so the dispatcher must not pick the
+	// delivery up before it would retry it.
+	d := &entities.WebhookDelivery{
+		WebhookID:     w.ID,
+		EventType:     entities.EventWebhookTest,
+		Payload:       payload,
+		Status:        entities.DeliveryPending,
+		NextAttemptAt: time.Now().Add(uc.backoff(1)),
+	}
+	if err := uc.repository.CreateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+
+	uc.deliver(ctx, w, d)
+
+	if err := uc.repository.SaveDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Run delivers due events every Interval until ctx is cancelled.
+func (uc *WebhookUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		uc.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *WebhookUsecase) tick(ctx context.Context) {
+	for {
+		// Deliveries of a batch are made one after another, so each claim
+		// has to last for the whole batch.
+		lease := time.Duration(uc.cfg.BatchSize)*uc.cfg.Timeout + time.Minute
+		processed, err := uc.repository.ProcessDue(ctx, uc.cfg.BatchSize, lease, uc.deliver)
+		if err != nil {
+			if ctx.Err() == nil {
+				uc.logger.Error("Failed to process webhook deliveries", "error", err.Error())
+			}
+			return
+		}
+
+		if processed < uc.cfg.BatchSize {
+			break
+		}
+	}
+
+	purged, err := uc.repository.PurgeDeliveries(ctx, time.Now().Add(-uc.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			uc.logger.Error("Failed to purge webhook deliveries", "error", err.Error())
+		}
+		return
+	}
+	if purged > 0 {
+		uc.logger.Debug("Purged webhook deliveries", "count", purged)
+	}
+}
+
+// deliver makes one attempt and records its outcome on d: delivered on a
+// 2xx response, otherwise pending with the next attempt scheduled, or dead
+// once the attempts are used up.
+func (uc *WebhookUsecase) deliver(ctx context.Context, w *entities.Webhook, d *entities.WebhookDelivery) {
+	now := time.Now()
+	d.Attempts++
+
+	status, err := uc.post(ctx, w, d, now)
+	if status != 0 {
+		d.ResponseStatus = &status
+	}
+
+	if err == nil {
+		d.Status = entities.DeliveryDelivered
+		d.DeliveredAt = &now
+		d.LastError = nil
+		uc.logger.Debug("Webhook delivered", "webhook_id", w.ID, "delivery_id", d.ID, "status", status)
+		return
+	}
+
+	message := err.Error()
+	if len(message) > maxWebhookErrorLength {
+		message = message[:maxWebhookErrorLength]
+	}
+	d.LastError = &message
+
+	if d.Attempts >= uc.cfg.MaxAttempts {
+		d.Status = entities.DeliveryDead
+		uc.logger.Warn("Webhook delivery failed for good", "webhook_id", w.ID, "delivery_id", d.ID,
+			"attempts", d.Attempts, "error", message)
+		return
+	}
+
+	d.Status = entities.DeliveryPending
+	d.NextAttemptAt = now.Add(uc.backoff(d.Attempts))
+	uc.logger.Info("Webhook delivery failed, will retry", "webhook_id", w.ID, "delivery_id", d.ID,
+		"attempts", d.Attempts, "next_attempt_at", d.NextAttemptAt, "error", message)
+}
+
+// backoff returns the delay before the attempt following the given one.
+func (uc *WebhookUsecase) backoff(attempts int) time.Duration {
+	delay := uc.cfg.MinBackoff
+	for i := 1; i < attempts && delay < uc.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, uc.cfg.MaxBackoff)
+}
+
+// post sends the signed payload and returns the response status, 0 when
+// there was no response.
+func (uc *WebhookUsecase) post(ctx context.Context, w *entities.Webhook, d *entities.WebhookDelivery, now time.Time) (int, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-service-webhooks")
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+sign(w.Secret, timestamp, d.Payload))
+
+	resp, err := uc.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
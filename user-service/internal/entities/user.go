@@ -4,7 +4,7 @@ type User struct {
 	ID           int    `json:"id"`
 	Username     string `json:"username"`
 	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash" db:"password_hash"`
+	PasswordHash string `json:"-" db:"password_hash"`
 }
 
 type CreateUserDto struct {
@@ -36,12 +36,14 @@ func (r *UserPostgresRepository) CreateUser(ctx context.Context, user *entities.
 
 func (r *UserPostgresRepository) GetByUserId(ctx context.Context, id int) (*entities.User, error) {
 	user := entities.User{}
-	query := "SELECT * FROM users WHERE id=$1"
-	r.logger.Debug("Executing query", "query", query, "id", user.ID)
-	err := r.db.Get(&user, query, id)
+	// The columns are listed since users has more of them than User has
+	// fields, which sqlx refuses to scan.
+	query := "SELECT id, username, email, password_hash FROM users WHERE id=$1"
+	r.logger.Debug("Executing query", "query", query, "id", id)
+	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			r.logger.Warn("Failed to get user in repository", "id", user.ID)
+			r.logger.Warn("Failed to get user in repository", "id", id)
 			return nil, app.NewAppError(app.ErrNotFound, "User not found in repository", err)
 		}
 	}
@@ -111,8 +111,12 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Println(err.Error())
-		w.WriteHeader(400)
-		w.Write([]byte("User is not found"))
+		var appErr *app.AppError
+		if errors.As(err, &appErr) && appErr.Type == app.ErrNotFound {
+			h.writeError(w, http.StatusNotFound, "User is not found", string(appErr.Type))
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, "Failed to fetch user", "internal")
 		return
 	}
 
@@ -124,6 +128,7 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 	w.Write(body)
 }